@@ -18,6 +18,16 @@ type Stmt interface {
 	stmtNode()
 }
 
+type TypeExpr interface {
+	Node
+	typeNode()
+}
+
+type Pattern interface {
+	Node
+	patternNode()
+}
+
 // Expressions
 type (
 	Ident struct {
@@ -67,8 +77,120 @@ type (
 		Then      *BlockExpr     // Executed if condition is true
 		Else      *BlockExpr     // Executed if condition is false
 	}
+
+	CallExpr struct {
+		Pos    token.Position // Position of callee
+		Callee Expr           // Expression evaluating to the function to call
+		Args   []Expr         // Arguments passed to function
+		Labels []string       // Names of named arguments, "" for positional arguments
+	}
+
+	LambdaExpr struct {
+		Pos    token.Position // Position of opening brace
+		Params []*Parameter   // Parameters of lambda
+		Body   *BlockExpr     // Body of lambda (last expression is returned)
+	}
+
+	RangeExpr struct {
+		Start     Expr           // Start of range
+		Pos       token.Position // Position of range operator
+		End       Expr           // End of range
+		Inclusive bool           // Whether end is included in range ('..' or '..<')
+		Step      Expr           // Distance between values in range (optional)
+	}
+
+	MatchExpr struct {
+		Pos     token.Position // Position of 'match'
+		Subject Expr           // Value to match against patterns
+		Arms    []*MatchArm    // Arms tried in order
+	}
+
+	ListLiteral struct {
+		Pos      token.Position // Position of left bracket
+		Elements []Expr         // Elements of list
+	}
+
+	MapLiteral struct {
+		Pos    token.Position // Position of left bracket
+		Keys   []Expr         // Keys of entries
+		Values []Expr         // Values of entries, in the same order as keys
+	}
+
+	ThisExpr struct {
+		Pos token.Position // Position of 'this'
+	}
+
+	IndexExpr struct {
+		Pos    token.Position // Position of left bracket
+		Object Expr           // Expression to index into
+		Index  Expr           // Index of element
+	}
+
+	GetExpr struct {
+		Pos    token.Position // Position of member name
+		Object Expr           // Expression to access member of
+		Name   string         // Name of member
+		Safe   bool           // Whether access is skipped when object is null ('?.')
+	}
+
+	ElvisExpr struct {
+		Left  Expr           // Value if not null
+		Pos   token.Position // Position of '?:'
+		Right Expr           // Value if left is null
+	}
+
+	NonNullExpr struct {
+		Pos  token.Position // Position of '!!'
+		Expr Expr           // Expression asserted to not be null
+	}
+
+	IsExpr struct {
+		Expr Expr           // Value to check
+		Pos  token.Position // Position of 'is'
+		Type TypeExpr       // Type value is checked against
+	}
+
+	CastExpr struct {
+		Expr Expr           // Value to cast
+		Pos  token.Position // Position of 'as'
+		Type TypeExpr       // Type value is cast to
+		Safe bool           // Whether failed casts give null ('as?') instead of an error
+	}
+
+	UnwrapExpr struct {
+		Pos  token.Position // Position of '?'
+		Expr Expr           // Result to unwrap, errors are returned from the enclosing function
+	}
+
+	TryExpr struct {
+		Pos     token.Position // Position of 'try'
+		Body    *BlockExpr     // Block that may throw
+		Catches []*CatchClause // Handlers tried in order when body throws
+		Finally *BlockExpr     // Always executed after body and handlers (optional)
+	}
 )
 
+// Arm of match expression
+type MatchArm struct {
+	Pos     token.Position // Position of pattern
+	Pattern Pattern        // Pattern to match subject against
+	Guard   Expr           // Condition that must hold for arm to match (optional)
+	Body    Expr           // Value of arm (nil if arm falls through)
+	Fall    bool           // Whether arm continues into body of next arm
+}
+
+func (a *MatchArm) Position() token.Position { return a.Pos }
+
+// Handler of try expression
+type CatchClause struct {
+	Pos  token.Position // Position of 'catch'
+	Name string         // Identifier bound to caught error
+	Type TypeExpr       // Type of errors handled by clause
+	Body *BlockExpr     // Value of try expression when clause handles error
+}
+
+func (c *CatchClause) Position() token.Position { return c.Pos }
+
 func (e *Ident) Position() token.Position        { return e.Pos }
 func (e *LiteralExpr) Position() token.Position  { return e.Pos }
 func (e *BinaryExpr) Position() token.Position   { return e.Pos }
@@ -77,6 +199,21 @@ func (e *UnaryExpr) Position() token.Position    { return e.Pos }
 func (e *BlockExpr) Position() token.Position    { return e.Pos }
 func (e *IfExpr) Position() token.Position       { return e.Pos }
 func (e *LogicalExpr) Position() token.Position  { return e.Pos }
+func (e *CallExpr) Position() token.Position     { return e.Pos }
+func (e *LambdaExpr) Position() token.Position   { return e.Pos }
+func (e *RangeExpr) Position() token.Position    { return e.Pos }
+func (e *MatchExpr) Position() token.Position    { return e.Pos }
+func (e *ListLiteral) Position() token.Position  { return e.Pos }
+func (e *MapLiteral) Position() token.Position   { return e.Pos }
+func (e *ThisExpr) Position() token.Position     { return e.Pos }
+func (e *IndexExpr) Position() token.Position    { return e.Pos }
+func (e *GetExpr) Position() token.Position      { return e.Pos }
+func (e *ElvisExpr) Position() token.Position    { return e.Pos }
+func (e *NonNullExpr) Position() token.Position  { return e.Pos }
+func (e *IsExpr) Position() token.Position       { return e.Pos }
+func (e *CastExpr) Position() token.Position     { return e.Pos }
+func (e *TryExpr) Position() token.Position      { return e.Pos }
+func (e *UnwrapExpr) Position() token.Position   { return e.Pos }
 
 func (e *Ident) exprNode()        {}
 func (e *LiteralExpr) exprNode()  {}
@@ -86,6 +223,21 @@ func (e *UnaryExpr) exprNode()    {}
 func (e *BlockExpr) exprNode()    {}
 func (e *IfExpr) exprNode()       {}
 func (e *LogicalExpr) exprNode()  {}
+func (e *CallExpr) exprNode()     {}
+func (e *LambdaExpr) exprNode()   {}
+func (e *RangeExpr) exprNode()    {}
+func (e *MatchExpr) exprNode()    {}
+func (e *ListLiteral) exprNode()  {}
+func (e *MapLiteral) exprNode()   {}
+func (e *ThisExpr) exprNode()     {}
+func (e *IndexExpr) exprNode()    {}
+func (e *GetExpr) exprNode()      {}
+func (e *ElvisExpr) exprNode()    {}
+func (e *NonNullExpr) exprNode()  {}
+func (e *IsExpr) exprNode()       {}
+func (e *CastExpr) exprNode()     {}
+func (e *TryExpr) exprNode()      {}
+func (e *UnwrapExpr) exprNode()   {}
 
 // Statements
 type (
@@ -93,7 +245,7 @@ type (
 		Pos      token.Position  // Position of decl type
 		Name     string          // Identifier for variable
 		DeclType token.TokenType // Declaration type: i.e. "val" or "var"
-		Type     *token.Token    // Name of type (optional)
+		Type     TypeExpr        // Type of variable (optional)
 		Value    Expr            // Initial value of variable (optional)
 	}
 
@@ -110,9 +262,26 @@ type (
 	AssignmentStmt struct {
 		Pos   token.Position // Position of identifier
 		Name  string         // Identifier to assign
+		Op    token.Token    // Assignment operator ('=' or compound operator like '+=')
 		Value Expr           // Value to assign to identifier
 	}
 
+	IndexAssignmentStmt struct {
+		Pos    token.Position // Position of left bracket
+		Object Expr           // Expression to index into
+		Index  Expr           // Index of element to assign
+		Op     token.Token    // Assignment operator ('=' or compound operator like '+=')
+		Value  Expr           // Value to assign to element
+	}
+
+	FieldAssignmentStmt struct {
+		Pos    token.Position // Position of field name
+		Object Expr           // Expression evaluating to instance
+		Name   string         // Name of field to assign
+		Op     token.Token    // Assignment operator ('=' or compound operator like '+=')
+		Value  Expr           // Value to assign to field
+	}
+
 	IfStmt struct {
 		Pos       token.Position // Position of 'if'
 		Condition Expr           // Determines which branch is executed
@@ -122,21 +291,224 @@ type (
 
 	WhileStmt struct {
 		Pos       token.Position // Position of 'while'
+		Label     string         // Label of loop (optional)
 		Condition Expr           // Execute block while condition is true
 		Block     *BlockStmt     // Block to execute
 	}
+
+	ForStmt struct {
+		Pos      token.Position // Position of 'for'
+		Label    string         // Label of loop (optional)
+		Name     string         // Identifier bound to each value, or to each key of a map
+		Value    string         // Identifier bound to each value of a map (optional)
+		Iterable Expr           // Expression to iterate over
+		Block    *BlockStmt     // Block to execute for each value
+	}
+
+	BreakStmt struct {
+		Pos   token.Position // Position of 'break'
+		Label string         // Label of loop to exit (optional)
+	}
+
+	ContinueStmt struct {
+		Pos   token.Position // Position of 'continue'
+		Label string         // Label of loop to continue (optional)
+	}
+
+	FunDeclaration struct {
+		Pos        token.Position // Position of 'fun'
+		Name       string         // Identifier for function
+		TypeParams []*TypeParam   // Type parameters of generic function (optional)
+		Params     []*Parameter   // Parameters of function
+		ReturnType TypeExpr       // Return type (optional)
+		Body       *BlockStmt     // Function body
+	}
+
+	ClassDeclaration struct {
+		Pos        token.Position    // Position of 'class'
+		Data       bool              // Declared with 'data', compared and printed by fields
+		Name       string            // Name of class
+		TypeParams []*TypeParam      // Type parameters of generic class (optional)
+		Fields     []*Field          // Fields declared by primary constructor
+		Interfaces []*NamedType      // Interfaces implemented by class (optional)
+		Methods    []*FunDeclaration // Methods of class
+	}
+
+	InterfaceDeclaration struct {
+		Pos     token.Position    // Position of 'interface'
+		Name    string            // Name of interface
+		Methods []*FunDeclaration // Method signatures required by interface (without bodies)
+	}
+
+	EnumDeclaration struct {
+		Pos      token.Position // Position of 'enum'
+		Name     string         // Name of enum
+		Variants []*Variant     // Variants of enum
+	}
+
+	ReturnStmt struct {
+		Pos   token.Position // Position of 'return'
+		Value Expr           // Value to return (optional)
+	}
+
+	ThrowStmt struct {
+		Pos   token.Position // Position of 'throw'
+		Value Expr           // Error to throw
+	}
+)
+
+// Function parameter
+type Parameter struct {
+	Pos  token.Position // Position of identifier
+	Name string         // Identifier for parameter
+	Type TypeExpr       // Type of parameter (optional for lambdas)
+}
+
+func (p *Parameter) Position() token.Position { return p.Pos }
+
+// Type parameter of generic function or class
+type TypeParam struct {
+	Pos  token.Position // Position of identifier
+	Name string         // Name of type parameter
+}
+
+func (t *TypeParam) Position() token.Position { return t.Pos }
+
+// Variant of enum
+type Variant struct {
+	Pos    token.Position // Position of identifier
+	Name   string         // Name of variant
+	Fields []*Parameter   // Values carried by variant
+}
+
+func (v *Variant) Position() token.Position { return v.Pos }
+
+// Field of class declared in primary constructor
+type Field struct {
+	Pos      token.Position  // Position of identifier
+	Name     string          // Identifier for field
+	DeclType token.TokenType // 'val' or 'var'
+	Type     TypeExpr        // Type of field
+}
+
+func (s *VarDeclaration) Position() token.Position       { return s.Pos }
+func (s *ExprStmt) Position() token.Position             { return s.Pos }
+func (s *BlockStmt) Position() token.Position            { return s.Pos }
+func (s *AssignmentStmt) Position() token.Position       { return s.Pos }
+func (s *IndexAssignmentStmt) Position() token.Position  { return s.Pos }
+func (s *FieldAssignmentStmt) Position() token.Position  { return s.Pos }
+func (s *ClassDeclaration) Position() token.Position     { return s.Pos }
+func (s *EnumDeclaration) Position() token.Position      { return s.Pos }
+func (s *InterfaceDeclaration) Position() token.Position { return s.Pos }
+func (s *IfStmt) Position() token.Position               { return s.Pos }
+func (s *WhileStmt) Position() token.Position            { return s.Pos }
+func (s *ForStmt) Position() token.Position              { return s.Pos }
+func (s *BreakStmt) Position() token.Position            { return s.Pos }
+func (s *ContinueStmt) Position() token.Position         { return s.Pos }
+func (s *FunDeclaration) Position() token.Position       { return s.Pos }
+func (s *ReturnStmt) Position() token.Position           { return s.Pos }
+func (s *ThrowStmt) Position() token.Position            { return s.Pos }
+
+func (s *VarDeclaration) stmtNode()       {}
+func (s *ExprStmt) stmtNode()             {}
+func (s *BlockStmt) stmtNode()            {}
+func (s *AssignmentStmt) stmtNode()       {}
+func (s *IndexAssignmentStmt) stmtNode()  {}
+func (s *FieldAssignmentStmt) stmtNode()  {}
+func (s *ClassDeclaration) stmtNode()     {}
+func (s *EnumDeclaration) stmtNode()      {}
+func (s *InterfaceDeclaration) stmtNode() {}
+func (s *IfStmt) stmtNode()               {}
+func (s *WhileStmt) stmtNode()            {}
+func (s *ForStmt) stmtNode()              {}
+func (s *BreakStmt) stmtNode()            {}
+func (s *ContinueStmt) stmtNode()         {}
+func (s *FunDeclaration) stmtNode()       {}
+func (s *ReturnStmt) stmtNode()           {}
+func (s *ThrowStmt) stmtNode()            {}
+
+// Types
+type (
+	NamedType struct {
+		Pos  token.Position // Position of identifier
+		Name string         // Name of type
+		Args []TypeExpr     // Type arguments, e.g. 'int' in List<int> (optional)
+	}
+
+	ListType struct {
+		Pos  token.Position // Position of left bracket
+		Elem TypeExpr       // Type of elements
+	}
+
+	MapType struct {
+		Pos   token.Position // Position of left bracket
+		Key   TypeExpr       // Type of keys
+		Value TypeExpr       // Type of values
+	}
+
+	FunctionType struct {
+		Pos    token.Position // Position of left paren
+		Params []TypeExpr     // Parameter types
+		Return TypeExpr       // Return type
+	}
+
+	NullableType struct {
+		Pos  token.Position // Position of underlying type
+		Elem TypeExpr       // Type that is made nullable
+	}
+)
+
+func (t *NamedType) Position() token.Position    { return t.Pos }
+func (t *FunctionType) Position() token.Position { return t.Pos }
+func (t *ListType) Position() token.Position     { return t.Pos }
+func (t *MapType) Position() token.Position      { return t.Pos }
+func (t *NullableType) Position() token.Position { return t.Pos }
+
+func (t *NamedType) typeNode()    {}
+func (t *FunctionType) typeNode() {}
+func (t *ListType) typeNode()     {}
+func (t *MapType) typeNode()      {}
+func (t *NullableType) typeNode() {}
+
+// Patterns
+type (
+	WildcardPattern struct {
+		Pos token.Position // Position of '_'
+	}
+
+	LiteralPattern struct {
+		Pos   token.Position // Position of literal
+		Value *LiteralExpr   // Value to compare with
+	}
+
+	RangePattern struct {
+		Pos       token.Position // Position of start
+		Start     *LiteralExpr   // Start of range
+		End       *LiteralExpr   // End of range
+		Inclusive bool           // Whether end is included in range
+	}
+
+	BindingPattern struct {
+		Pos  token.Position // Position of identifier
+		Name string         // Identifier bound to matched value
+	}
+
+	VariantPattern struct {
+		Pos     token.Position // Position of enum name, or of variant name without enum
+		Enum    string         // Name of enum ("" for variants of results)
+		Variant string         // Name of variant
+		Fields  []Pattern      // Patterns matched against values of variant
+	}
 )
 
-func (s *VarDeclaration) Position() token.Position { return s.Pos }
-func (s *ExprStmt) Position() token.Position       { return s.Pos }
-func (s *BlockStmt) Position() token.Position      { return s.Pos }
-func (s *AssignmentStmt) Position() token.Position { return s.Pos }
-func (s *IfStmt) Position() token.Position         { return s.Pos }
-func (s *WhileStmt) Position() token.Position      { return s.Pos }
-
-func (s *VarDeclaration) stmtNode() {}
-func (s *ExprStmt) stmtNode()       {}
-func (s *BlockStmt) stmtNode()      {}
-func (s *AssignmentStmt) stmtNode() {}
-func (s *IfStmt) stmtNode()         {}
-func (s *WhileStmt) stmtNode()      {}
+func (p *WildcardPattern) Position() token.Position { return p.Pos }
+func (p *LiteralPattern) Position() token.Position  { return p.Pos }
+func (p *RangePattern) Position() token.Position    { return p.Pos }
+func (p *BindingPattern) Position() token.Position  { return p.Pos }
+func (p *VariantPattern) Position() token.Position  { return p.Pos }
+
+func (p *WildcardPattern) patternNode() {}
+func (p *LiteralPattern) patternNode()  {}
+func (p *RangePattern) patternNode()    {}
+func (p *BindingPattern) patternNode()  {}
+func (p *VariantPattern) patternNode()  {}
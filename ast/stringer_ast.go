@@ -1,9 +1,132 @@
 package ast
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
-func (e *Ident) String() string          { return e.Name }
-func (e *LiteralExpr) String() string    { return e.Value }
-func (e *BinaryExpr) String() string     { return fmt.Sprintf("(%s %v %v)", e.Op.Value, e.Left, e.Right) }
-func (e *GroupingExpr) String() string   { return fmt.Sprintf("(%v)", e.Expr) }
-func (e *UnaryExpr) String() string      { return fmt.Sprintf("(%s%v)", e.Op.Value, e.Expr) }
+func (e *Ident) String() string        { return e.Name }
+func (e *LiteralExpr) String() string  { return e.Value }
+func (e *BinaryExpr) String() string   { return fmt.Sprintf("(%s %v %v)", e.Op.Value, e.Left, e.Right) }
+func (e *GroupingExpr) String() string { return fmt.Sprintf("(%v)", e.Expr) }
+func (e *UnaryExpr) String() string    { return fmt.Sprintf("(%s%v)", e.Op.Value, e.Expr) }
+
+func (e *CallExpr) String() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		if e.Labels[i] != "" {
+			args[i] = fmt.Sprintf("%s = %v", e.Labels[i], arg)
+		} else {
+			args[i] = fmt.Sprintf("%v", arg)
+		}
+	}
+	return fmt.Sprintf("%v(%s)", e.Callee, strings.Join(args, ", "))
+}
+
+func (e *ThisExpr) String() string  { return "this" }
+func (e *IndexExpr) String() string { return fmt.Sprintf("%v[%v]", e.Object, e.Index) }
+func (e *GetExpr) String() string {
+	if e.Safe {
+		return fmt.Sprintf("%v?.%s", e.Object, e.Name)
+	}
+	return fmt.Sprintf("%v.%s", e.Object, e.Name)
+}
+
+func (e *ListLiteral) String() string {
+	elements := make([]string, len(e.Elements))
+	for i, element := range e.Elements {
+		elements[i] = fmt.Sprintf("%v", element)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+func (e *MapLiteral) String() string {
+	if len(e.Keys) == 0 {
+		return "[:]"
+	}
+
+	entries := make([]string, len(e.Keys))
+	for i, key := range e.Keys {
+		entries[i] = fmt.Sprintf("%v: %v", key, e.Values[i])
+	}
+	return fmt.Sprintf("[%s]", strings.Join(entries, ", "))
+}
+
+func (e *LogicalExpr) String() string { return fmt.Sprintf("(%s %v %v)", e.Op.Value, e.Left, e.Right) }
+func (e *BlockExpr) String() string   { return "{ ... }" }
+func (e *IfExpr) String() string {
+	if e.Else != nil {
+		return fmt.Sprintf("if %v %v else %v", e.Condition, e.Then, e.Else)
+	}
+	return fmt.Sprintf("if %v %v", e.Condition, e.Then)
+}
+
+func (e *LambdaExpr) String() string {
+	if len(e.Params) == 0 {
+		return "{ -> ... }"
+	}
+
+	params := make([]string, len(e.Params))
+	for i, param := range e.Params {
+		params[i] = param.Name
+	}
+	return fmt.Sprintf("{ %s -> ... }", strings.Join(params, ", "))
+}
+
+func (e *RangeExpr) String() string {
+	op := "..<"
+	if e.Inclusive {
+		op = ".."
+	}
+
+	if e.Step != nil {
+		return fmt.Sprintf("(%v%s%v step %v)", e.Start, op, e.End, e.Step)
+	}
+	return fmt.Sprintf("(%v%s%v)", e.Start, op, e.End)
+}
+
+func (e *MatchExpr) String() string  { return fmt.Sprintf("match %v { ... }", e.Subject) }
+func (e *IsExpr) String() string     { return fmt.Sprintf("(%v is %v)", e.Expr, e.Type) }
+func (e *UnwrapExpr) String() string { return fmt.Sprintf("%v?", e.Expr) }
+func (e *TryExpr) String() string    { return "try { ... }" }
+
+func (t *NamedType) String() string {
+	if len(t.Args) == 0 {
+		return t.Name
+	}
+
+	args := make([]string, len(t.Args))
+	for i, arg := range t.Args {
+		args[i] = fmt.Sprintf("%v", arg)
+	}
+	return fmt.Sprintf("%s<%s>", t.Name, strings.Join(args, ", "))
+}
+
+func (t *ListType) String() string { return fmt.Sprintf("[%v]", t.Elem) }
+func (t *MapType) String() string  { return fmt.Sprintf("[%v: %v]", t.Key, t.Value) }
+
+func (t *FunctionType) String() string {
+	params := make([]string, len(t.Params))
+	for i, param := range t.Params {
+		params[i] = fmt.Sprintf("%v", param)
+	}
+	return fmt.Sprintf("(%s) -> %v", strings.Join(params, ", "), t.Return)
+}
+
+func (t *NullableType) String() string {
+	// Function types are parenthesized so '?' applies to the whole type
+	if _, ok := t.Elem.(*FunctionType); ok {
+		return fmt.Sprintf("(%v)?", t.Elem)
+	}
+	return fmt.Sprintf("%v?", t.Elem)
+}
+
+func (e *ElvisExpr) String() string   { return fmt.Sprintf("(%v ?: %v)", e.Left, e.Right) }
+func (e *NonNullExpr) String() string { return fmt.Sprintf("%v!!", e.Expr) }
+
+func (e *CastExpr) String() string {
+	if e.Safe {
+		return fmt.Sprintf("(%v as? %v)", e.Expr, e.Type)
+	}
+	return fmt.Sprintf("(%v as %v)", e.Expr, e.Type)
+}
@@ -0,0 +1,113 @@
+package diagnostic
+
+import (
+	"fmt"
+	"interpreter/token"
+)
+
+// How serious a diagnostic is
+type Severity int
+
+const (
+	Error   Severity = iota // Problem that stops the program from running
+	Warning                 // Suspicious code that does not stop the program
+	Note                    // Additional information
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	case Note:
+		return "note"
+	}
+
+	panic(fmt.Sprintf("unexpected diagnostic.Severity: %#v", s))
+}
+
+// Source code from start up to, but not including, end
+type Span struct {
+	Start token.Position
+	End   token.Position
+}
+
+// Get span of the token at pos
+func At(pos token.Position) Span {
+	return Span{
+		Start: pos,
+		End:   pos.End(),
+	}
+}
+
+// Secondary span pointing to code related to a diagnostic, e.g. an earlier declaration
+type Label struct {
+	Span    Span
+	Message string
+}
+
+// Suggested edit replacing the code of a span
+// Empty spans insert the replacement, empty replacements remove the code
+type FixIt struct {
+	Span        Span
+	Replacement string
+}
+
+// Problem found in a program, with the code it refers to
+type Diagnostic struct {
+	Severity Severity
+	File     string   // Name of file
+	Span     Span     // Code the diagnostic is about
+	Message  string   // Description of problem
+	Labels   []Label  // Related code
+	Notes    []string // Further explanations
+	FixIts   []FixIt  // Suggested edits
+}
+
+// Create diagnostic with message about span in file
+func New(severity Severity, file string, span Span, message string) *Diagnostic {
+	return &Diagnostic{
+		Severity: severity,
+		File:     file,
+		Span:     span,
+		Message:  message,
+		Labels:   []Label{},
+		Notes:    []string{},
+		FixIts:   []FixIt{},
+	}
+}
+
+// Create error with message about span in file
+func NewError(file string, span Span, message string) *Diagnostic {
+	return New(Error, file, span, message)
+}
+
+// Add label with message to span
+func (d *Diagnostic) WithLabel(span Span, message string) *Diagnostic {
+	d.Labels = append(d.Labels, Label{
+		Span:    span,
+		Message: message,
+	})
+	return d
+}
+
+// Add note with further explanation
+func (d *Diagnostic) WithNote(note string) *Diagnostic {
+	d.Notes = append(d.Notes, note)
+	return d
+}
+
+// Add suggestion to replace the code of span
+func (d *Diagnostic) WithFixIt(span Span, replacement string) *Diagnostic {
+	d.FixIts = append(d.FixIts, FixIt{
+		Span:        span,
+		Replacement: replacement,
+	})
+	return d
+}
+
+// Format diagnostic on one line, without source code
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s:%d:%d - %s", d.File, d.Span.Start.Row, d.Span.Start.Column, d.Message)
+}
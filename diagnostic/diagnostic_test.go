@@ -0,0 +1,48 @@
+package diagnostic
+
+import (
+	"interpreter/token"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	source := "val x = 1\n\tval y = x + \"a\";\n"
+
+	pos := token.Position{Row: 2, Column: 12, Offset: 21, Length: 1}
+	operand := token.Position{Row: 2, Column: 14, Offset: 23, Length: 3}
+	end := token.Position{Row: 1, Column: 10, Offset: 9}
+
+	d := NewError("test", At(pos), "Invalid operation").
+		WithLabel(At(operand), "has type string").
+		WithNote("Strings cannot be added to numbers").
+		WithFixIt(Span{Start: end, End: end}, ";").
+		WithFixIt(At(operand), "1")
+
+	expected := "test:2:12 - error: Invalid operation\n" +
+		"1 | val x = 1\n" +
+		"  |          +\n" +
+		"2 | \tval y = x + \"a\";\n" +
+		"  | \t          ^\n" +
+		"  | \t            ~~~ has type string\n" +
+		"  = note: Strings cannot be added to numbers\n" +
+		"  = help: insert \";\"\n" +
+		"  = help: replace \"\\\"a\\\"\" with \"1\"\n"
+
+	if actual := d.Render([]byte(source), false); actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+
+	if actual := d.Error(); actual != "test:2:12 - Invalid operation" {
+		t.Errorf("Expected error %q, got %q", "test:2:12 - Invalid operation", actual)
+	}
+}
+
+func TestRenderOutsideSource(t *testing.T) {
+	pos := token.Position{Row: 3, Column: 1}
+	d := New(Warning, "test", At(pos), "Unused variable")
+
+	expected := "test:3:1 - warning: Unused variable\n"
+	if actual := d.Render([]byte("val x = 1;"), false); actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
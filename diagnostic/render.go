@@ -0,0 +1,192 @@
+package diagnostic
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape codes
+const (
+	reset   = "\x1b[0m"
+	bold    = "\x1b[1m"
+	red     = "\x1b[1;31m"
+	green   = "\x1b[1;32m"
+	magenta = "\x1b[1;35m"
+	cyan    = "\x1b[1;36m"
+	blue    = "\x1b[1;34m"
+)
+
+// State of rendering a diagnostic
+type renderer struct {
+	sb    strings.Builder
+	lines []string // Lines of source
+	width int      // Width of row numbers
+	color bool     // Use ANSI escape codes
+	row   int      // Row of the last source line written
+}
+
+// Check if file is a terminal, where diagnostics can be colored
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Underlined span of a diagnostic
+type underline struct {
+	span    Span
+	char    byte   // Character marking the start of the span
+	color   string // Color of underline
+	message string // Text after underline
+}
+
+// Render diagnostic with the lines of source its spans are on, in source order
+// The span of the diagnostic is underlined with '^~~~', labels with '~~~~'
+// Fix-its on lines not shown otherwise are marked with '+', so their edit is seen in context
+// Color adds ANSI escape codes, for terminals
+func (d *Diagnostic) Render(source []byte, color bool) string {
+	underlines := []underline{{d.Span, '^', green, ""}}
+	for _, label := range d.Labels {
+		underlines = append(underlines, underline{label.Span, '~', blue, label.Message})
+	}
+	for _, fix := range d.FixIts {
+		shown := slices.ContainsFunc(underlines, func(m underline) bool { return m.span.Start.Row == fix.Span.Start.Row })
+		if !shown {
+			underlines = append(underlines, underline{fix.Span, '+', green, ""})
+		}
+	}
+	slices.SortStableFunc(underlines, func(a underline, b underline) int { return a.span.Start.Row - b.span.Start.Row })
+
+	r := &renderer{
+		lines: strings.Split(string(source), "\n"),
+		width: len(strconv.Itoa(underlines[len(underlines)-1].span.Start.Row)),
+		color: color,
+	}
+
+	pos := d.Span.Start
+	r.write(bold, fmt.Sprintf("%s:%d:%d - ", d.File, pos.Row, pos.Column))
+	r.write(d.Severity.color(), d.Severity.String()+":")
+	r.write(bold, " "+d.Message)
+	r.sb.WriteString("\n")
+
+	for _, m := range underlines {
+		r.snippet(m.span, m.char, m.color, m.message)
+	}
+
+	for _, note := range d.Notes {
+		r.note("note", note)
+	}
+	for _, fix := range d.FixIts {
+		r.note("help", r.describe(fix))
+	}
+
+	return r.sb.String()
+}
+
+// Color of severity in headers
+func (s Severity) color() string {
+	switch s {
+	case Error:
+		return red
+	case Warning:
+		return magenta
+	default:
+		return cyan
+	}
+}
+
+// Write text, colored if enabled
+func (r *renderer) write(color string, text string) {
+	if r.color {
+		r.sb.WriteString(color + text + reset)
+	} else {
+		r.sb.WriteString(text)
+	}
+}
+
+// Write source line of span, underlined from its start with mark followed by '~'
+// Lines are only written once for consecutive spans on the same row
+func (r *renderer) snippet(span Span, mark byte, color string, message string) {
+	row := span.Start.Row
+	if row < 1 || row > len(r.lines) {
+		return
+	}
+
+	line := strings.TrimRight(r.lines[row-1], "\r")
+	if row != r.row {
+		r.write(blue, fmt.Sprintf("%*d | ", r.width, row))
+		r.sb.WriteString(line + "\n")
+		r.row = row
+	}
+
+	r.write(blue, strings.Repeat(" ", r.width)+" | ")
+	indent, length := r.columns(line, span)
+	r.sb.WriteString(indent)
+	r.write(color, string(mark)+strings.Repeat("~", length-1))
+	if message != "" {
+		r.write(color, " "+message)
+	}
+	r.sb.WriteString("\n")
+}
+
+// Get whitespace before span on line and the number of characters it underlines
+// Tabs are kept so the underline lines up with the source
+// Spans continuing on later lines are underlined to the end of the line, empty spans with one character
+func (r *renderer) columns(line string, span Span) (string, int) {
+	start := min(max(span.Start.Column-1, 0), len(line))
+	end := start
+	if span.End.Row > span.Start.Row {
+		end = len(line)
+	} else if span.End.Row == span.Start.Row {
+		end = min(max(span.End.Column-1, start), len(line))
+	}
+
+	var indent strings.Builder
+	for _, char := range line[:start] {
+		if char == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+
+	return indent.String(), max(utf8.RuneCountInString(line[start:end]), 1)
+}
+
+// Write note below source lines
+func (r *renderer) note(kind string, text string) {
+	r.write(blue, strings.Repeat(" ", r.width)+" = ")
+	r.write(bold, kind+":")
+	r.sb.WriteString(" " + text + "\n")
+}
+
+// Describe edit suggested by fix-it
+func (r *renderer) describe(fix FixIt) string {
+	code := r.code(fix.Span)
+	switch {
+	case code == "":
+		return fmt.Sprintf("insert %q", fix.Replacement)
+	case fix.Replacement == "":
+		return fmt.Sprintf("remove %q", code)
+	default:
+		return fmt.Sprintf("replace %q with %q", code, fix.Replacement)
+	}
+}
+
+// Get code of span on a single line
+// Returns an empty string for empty spans and spans over several lines
+func (r *renderer) code(span Span) string {
+	row := span.Start.Row
+	if row < 1 || row > len(r.lines) || span.End.Row != row {
+		return ""
+	}
+
+	line := r.lines[row-1]
+	start := min(max(span.Start.Column-1, 0), len(line))
+	end := min(max(span.End.Column-1, start), len(line))
+
+	return line[start:end]
+}
@@ -0,0 +1,92 @@
+package interpret
+
+import "interpreter/ast"
+
+// User defined class
+// Classes are types, and values that construct instances when called
+type Class struct {
+	decl    *ast.ClassDeclaration // Declaration of class
+	closure *Environment          // Environment class was declared in
+	file    string                // File class was declared in
+}
+
+func (c *Class) Name() string {
+	return c.decl.Name
+}
+
+func (c *Class) Type() {}
+
+func (c *Class) value() {}
+
+func NewClass(decl *ast.ClassDeclaration, closure *Environment, file string) *Class {
+	return &Class{
+		decl:    decl,
+		closure: closure,
+		file:    file,
+	}
+}
+
+// Get method declaration with name, or nil if class has no such method
+func (c *Class) method(name string) *ast.FunDeclaration {
+	for _, m := range c.decl.Methods {
+		if m.Name == name {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// Get method with name bound to instance, or nil if its class has no such method
+func (v *Instance) method(name string) Value {
+	decl := v.class.method(name)
+	if decl == nil {
+		return nil
+	}
+
+	// Bind 'this' in the closure of the method
+	env := NewEnvironmentWithParent(v.class.closure)
+	env.define("this", v)
+	return NewFunction(decl, env, v.class.file)
+}
+
+// Check if class declares to implement interface
+func (c *Class) implements(i *Interface) bool {
+	for _, n := range c.decl.Interfaces {
+		if c.closure.lookupType(n.Name) == i {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Instance of user defined class
+type Instance struct {
+	class  *Class           // Class of instance
+	fields map[string]Value // Values of fields by name
+}
+
+func (i *Instance) Name() string {
+	return i.class.Name()
+}
+
+func (i *Instance) value() {}
+
+func NewInstance(class *Class, fields map[string]Value) *Instance {
+	return &Instance{
+		class:  class,
+		fields: fields,
+	}
+}
+
+// Copy method of data class instance
+type Copy struct {
+	instance *Instance // Instance to copy
+}
+
+func (c *Copy) Name() string {
+	return "function"
+}
+
+func (c *Copy) value() {}
@@ -0,0 +1,21 @@
+package interpret
+
+// Used to unwind the call stack when returning from a function
+type returnValue struct {
+	value Value
+}
+
+// Used to unwind to the enclosing loop on 'break'
+type breakLoop struct {
+	label string // Label of loop to exit ("" for innermost)
+}
+
+// Used to unwind to the enclosing loop on 'continue'
+type continueLoop struct {
+	label string // Label of loop to continue ("" for innermost)
+}
+
+// Check if a break or continue with label targets loop
+func targets(label string, loop string) bool {
+	return label == "" || label == loop
+}
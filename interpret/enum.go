@@ -0,0 +1,55 @@
+package interpret
+
+import "interpreter/ast"
+
+// User defined enum
+// Enums are types, and values giving access to their variants
+type Enum struct {
+	decl *ast.EnumDeclaration // Declaration of enum
+}
+
+func (e *Enum) Name() string {
+	return e.decl.Name
+}
+
+func (e *Enum) Type() {}
+
+func (e *Enum) value() {}
+
+func NewEnum(decl *ast.EnumDeclaration) *Enum {
+	return &Enum{
+		decl: decl,
+	}
+}
+
+// Get variant declaration with name, or nil if enum has no such variant
+func (e *Enum) variant(name string) *ast.Variant {
+	for _, v := range e.decl.Variants {
+		if v.Name == name {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// Value of enum
+type Variant struct {
+	enum   *Enum        // Enum of variant
+	decl   *ast.Variant // Declaration of variant
+	fields []Value      // Values carried by variant
+}
+
+func (v *Variant) Name() string {
+	return v.enum.Name()
+}
+
+func (v *Variant) value() {}
+
+func NewVariant(enum *Enum, decl *ast.Variant, fields []Value) *Variant {
+	return &Variant{
+		enum:   enum,
+		decl:   decl,
+		fields: fields,
+	}
+}
@@ -10,10 +10,17 @@ var outer *Environment
 
 func NewEnvironment() *Environment {
 	if outer == nil {
+		// Builtin functions are in an enclosing environment, so programs can redefine them
+		builtins := &Environment{
+			values: getBuiltins(),
+			types:  map[string]Type{"Error": errorInterface},
+			parent: nil,
+		}
+
 		outer = &Environment{
 			values: map[string]Value{},
 			types:  getInbuilts(),
-			parent: nil,
+			parent: builtins,
 		}
 	}
 
@@ -35,17 +42,27 @@ func (env *Environment) define(name string, value Value) {
 	env.values[name] = value
 }
 
+// Define type with name in current environment
+func (env *Environment) defineType(name string, t Type) {
+	env.types[name] = t
+}
+
 // Assign value to binding with identifier name in closest
 // enclosing scope where name is defined
-func (env *Environment) assign(name string, value Value) {
+// Returns false if name is not defined in any enclosing scope
+func (env *Environment) assign(name string, value Value) bool {
 	_, ok := env.values[name]
 
 	if ok {
 		env.values[name] = value
-		return
+		return true
+	}
+
+	if env.parent == nil {
+		return false
 	}
 
-	env.parent.assign(name, value)
+	return env.parent.assign(name, value)
 }
 
 // Lookup binding with name
@@ -0,0 +1,63 @@
+package interpret
+
+import (
+	"fmt"
+	"interpreter/diagnostic"
+	"interpreter/token"
+	"strings"
+)
+
+// Error raised while executing a program
+type RuntimeError struct {
+	File    string         // Name of file
+	Pos     token.Position // Position of the node that failed
+	Message string         // Description of error
+	Trace   []Frame        // Functions executing when the error was raised, outermost first
+	value   Value          // Value thrown with 'throw' (nil for errors raised by the interpreter)
+}
+
+// Function executing when runtime error was raised
+type Frame struct {
+	Function string         // Name of function, <main> for top level code and <lambda> for lambdas
+	File     string         // File of function
+	Pos      token.Position // Position execution reached in function
+}
+
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("%s:%d:%d - %s", e.File, e.Pos.Row, e.Pos.Column, e.Message)
+}
+
+// Get diagnostic of error, to render it with its source code
+func (e *RuntimeError) Diagnostic() *diagnostic.Diagnostic {
+	return diagnostic.NewError(e.File, diagnostic.At(e.Pos), e.Message)
+}
+
+// Format stack trace, with the most recent call last
+// Frames repeated by recursion are only listed a few times
+func (e *RuntimeError) StackTrace() string {
+	const shown = 3
+
+	var sb strings.Builder
+	sb.WriteString("Stack trace (most recent call last):\n")
+
+	repeated := 0
+	for n, f := range e.Trace {
+		if n > 0 && f == e.Trace[n-1] {
+			repeated++
+		} else {
+			repeated = 0
+		}
+
+		if repeated < shown {
+			sb.WriteString(fmt.Sprintf("    %s:%d:%d in %s\n", f.File, f.Pos.Row, f.Pos.Column, f.Function))
+		}
+
+		// Summarize the rest of a repetition at its end
+		last := n == len(e.Trace)-1 || e.Trace[n+1] != f
+		if last && repeated >= shown {
+			sb.WriteString(fmt.Sprintf("    [Previous frame repeated %d more times]\n", repeated-shown+1))
+		}
+	}
+
+	return sb.String()
+}
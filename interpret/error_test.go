@@ -0,0 +1,92 @@
+package interpret
+
+import (
+	"interpreter/lexer"
+	"interpreter/parser"
+	"interpreter/types"
+	"testing"
+)
+
+func TestStackTrace(t *testing.T) {
+	input := `
+fun f(n: int): int {
+    if n == 0 { return 1 / n; }
+    return f(n - 1);
+}
+fun g(): int { return f(5); }
+g();
+`
+
+	err := run(t, input)
+	if err == nil {
+		t.Fatalf("Expected division by zero")
+	}
+
+	// Calls of f(4) to f(0) are made from the same position
+	expected := []struct {
+		function    string
+		row, column int
+	}{
+		{"<main>", 7, 1},
+		{"g", 6, 23},
+		{"f", 4, 12},
+		{"f", 4, 12},
+		{"f", 4, 12},
+		{"f", 4, 12},
+		{"f", 4, 12},
+		{"f", 3, 26},
+	}
+
+	if len(err.Trace) != len(expected) {
+		t.Fatalf("Expected %d frames, found %d: %v", len(expected), len(err.Trace), err.Trace)
+	}
+	for n, f := range err.Trace {
+		e := expected[n]
+		if f.Function != e.function || f.File != "test" || f.Pos.Row != e.row || f.Pos.Column != e.column {
+			t.Errorf("Frame %d: expected %s at %d:%d, found %s at %s:%d:%d",
+				n, e.function, e.row, e.column, f.Function, f.File, f.Pos.Row, f.Pos.Column)
+		}
+	}
+
+	trace := `Stack trace (most recent call last):
+    test:7:1 in <main>
+    test:6:23 in g
+    test:4:12 in f
+    test:4:12 in f
+    test:4:12 in f
+    [Previous frame repeated 2 more times]
+    test:3:26 in f
+`
+	if got := err.StackTrace(); got != trace {
+		t.Errorf("Expected stack trace:\n%s\nfound:\n%s", trace, got)
+	}
+}
+
+// Lex, parse, typecheck and execute input, returning the runtime error that stopped it
+func run(t *testing.T, input string) *RuntimeError {
+	tokens, errors := lexer.NewLexer([]byte(input), "test").Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	program, errors := parser.NewParser(tokens, "test").Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected parser errors: %v", errors)
+	}
+
+	checker := types.NewChecker("test")
+	if !checker.Visit(program) {
+		t.Fatalf("Unexpected type errors: %v", checker.Errors)
+	}
+
+	err := NewInterpreter("test", checker.Types).Visit(program)
+	if err == nil {
+		return nil
+	}
+
+	runtimeErr, ok := err.(*RuntimeError)
+	if !ok {
+		t.Fatalf("Expected runtime error, found %v", err)
+	}
+	return runtimeErr
+}
@@ -0,0 +1,28 @@
+package interpret
+
+import "interpreter/ast"
+
+// Interface implemented by values that can be thrown
+var errorInterface = NewInterface(&ast.InterfaceDeclaration{Name: "Error"})
+
+// Runtime error raised by the interpreter, caught by a try expression
+// Implements Error, with the message of the runtime error
+type Exception struct {
+	err *RuntimeError // Caught error
+}
+
+func (e *Exception) Name() string {
+	return "RuntimeError"
+}
+
+func (e *Exception) value() {}
+
+// Get value caught by catch clauses when error is raised
+// Errors raised by the interpreter itself are caught as exceptions
+func (e *RuntimeError) caught() Value {
+	if e.value != nil {
+		return e.value
+	}
+
+	return &Exception{err: e}
+}
@@ -0,0 +1,60 @@
+package interpret
+
+import (
+	"fmt"
+	"interpreter/ast"
+	"interpreter/token"
+)
+
+// Maximum number of nested calls
+const maxDepth = 10000
+
+// Active call of function
+type frame struct {
+	name string         // Name of called function
+	file string         // File of the caller
+	call token.Position // Position of the call in the caller
+}
+
+// Enter function with name declared in file, called at node
+// Deep recursion stops with a runtime error before it exhausts the stack of the host
+func (i *Interpreter) enterFrame(name string, file string, call ast.Node) {
+	if len(i.frames) >= maxDepth {
+		i.error(fmt.Sprintf("Stack overflow, more than %d nested calls", maxDepth), call)
+	}
+
+	i.frames = append(i.frames, frame{
+		name: name,
+		file: i.file,
+		call: call.Position(),
+	})
+	i.file = file
+}
+
+// Return to the caller of the innermost function
+func (i *Interpreter) exitFrame() {
+	last := i.frames[len(i.frames)-1]
+	i.frames = i.frames[:len(i.frames)-1]
+	i.file = last.file
+}
+
+// Get stack trace of active calls, where execution of the innermost function is at pos
+// Each function is at the call of the next, top level code is named <main>
+func (i *Interpreter) trace(pos token.Position) []Frame {
+	trace := make([]Frame, 0, len(i.frames)+1)
+	name := "<main>"
+	for _, f := range i.frames {
+		trace = append(trace, Frame{
+			Function: name,
+			File:     f.file,
+			Pos:      f.call,
+		})
+		name = f.name
+	}
+
+	return append(trace, Frame{
+		Function: name,
+		File:     i.file,
+		Pos:      pos,
+	})
+}
@@ -0,0 +1,64 @@
+package interpret
+
+import "interpreter/ast"
+
+// User defined function
+type Function struct {
+	decl    *ast.FunDeclaration // Declaration of function
+	closure *Environment        // Environment function was declared in
+	file    string              // File function was declared in
+}
+
+func (f *Function) Name() string {
+	return "function"
+}
+
+func (f *Function) value() {}
+
+func NewFunction(decl *ast.FunDeclaration, closure *Environment, file string) Value {
+	return &Function{
+		decl:    decl,
+		closure: closure,
+		file:    file,
+	}
+}
+
+// Anonymous function capturing its enclosing environment
+type Lambda struct {
+	expr    *ast.LambdaExpr // Lambda expression
+	closure *Environment    // Environment lambda was created in
+	file    string          // File lambda was created in
+}
+
+func (l *Lambda) Name() string {
+	return "function"
+}
+
+func (l *Lambda) value() {}
+
+func NewLambda(expr *ast.LambdaExpr, closure *Environment, file string) Value {
+	return &Lambda{
+		expr:    expr,
+		closure: closure,
+		file:    file,
+	}
+}
+
+// Function implemented by the interpreter, e.g. methods of maps
+type Builtin struct {
+	name string                                                  // Name of function
+	fn   func(i *Interpreter, args []Value, call ast.Node) Value // Implementation of function, given the calling interpreter and the call for errors
+}
+
+func (b *Builtin) Name() string {
+	return "function"
+}
+
+func (b *Builtin) value() {}
+
+func NewBuiltin(name string, fn func(i *Interpreter, args []Value, call ast.Node) Value) Value {
+	return &Builtin{
+		name: name,
+		fn:   fn,
+	}
+}
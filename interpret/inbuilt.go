@@ -1,6 +1,11 @@
 package interpret
 
-import "fmt"
+import (
+	"fmt"
+	"interpreter/ast"
+	"math/big"
+	"strconv"
+)
 
 // Inbuilt
 type Inbuilt struct {
@@ -15,27 +20,68 @@ func (i *Inbuilt) Type() {}
 
 func getInbuilts() map[string]Type {
 	inbuilts := map[string]Type{}
-	types := []string{"int", "string", "real", "char", "boolean"}
+	types := []string{"int", "string", "real", "char", "boolean", "unit", "i8", "i16", "i32", "u8", "u16", "u32", "u64", "f32", "bigint", "decimal"}
 	for _, s := range types {
 		inbuilts[s] = &Inbuilt{name: s}
 	}
 
+	// Aliases of int and real
+	inbuilts["i64"] = inbuilts["int"]
+	inbuilts["f64"] = inbuilts["real"]
+
 	return inbuilts
 }
 
 func getInbuiltValue(i *Inbuilt) Value {
 	switch i.name {
-	case "int":
-		return &Integer{Value: 0}
-	case "real":
-		return &Real{Value: 0.0}
+	case "int", "i8", "i16", "i32", "u8", "u16", "u32", "u64":
+		return &Integer{Value: 0, Kind: intKinds[i.name]}
+	case "real", "f32":
+		return &Real{Value: 0.0, Single: i.name == "f32"}
+	case "bigint":
+		return &BigInt{Value: new(big.Int)}
+	case "decimal":
+		return &Decimal{Value: new(big.Rat)}
 	case "string":
 		return &String{Value: ""}
 	case "char":
 		return &Char{Value: '\000'}
 	case "boolean":
 		return &Boolean{Value: false}
+	case "unit":
+		return &Unit{}
 	default:
 		panic(fmt.Sprintf("Unknown inbuilt: %s\n", i.name))
 	}
 }
+
+// Get functions implemented by the interpreter, available in every program
+// They are shared by all interpreters, so errors are reported by the one calling them
+func getBuiltins() map[string]Value {
+	return map[string]Value{
+		"parseInt": NewBuiltin("parseInt", func(i *Interpreter, args []Value, call ast.Node) Value {
+			s := args[0].(*String).Value
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				i.error(fmt.Sprintf("Cannot parse %q as int", s), call)
+			}
+
+			return NewInteger(int(n))
+		}),
+		"parseReal": NewBuiltin("parseReal", func(i *Interpreter, args []Value, call ast.Node) Value {
+			s := args[0].(*String).Value
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				i.error(fmt.Sprintf("Cannot parse %q as real", s), call)
+			}
+
+			return NewReal(f)
+		}),
+		"Ok": NewBuiltin("Ok", func(_ *Interpreter, args []Value, _ ast.Node) Value {
+			return NewResult(true, args[0])
+		}),
+		"Err": NewBuiltin("Err", func(_ *Interpreter, args []Value, _ ast.Node) Value {
+			return NewResult(false, args[0])
+		}),
+	}
+}
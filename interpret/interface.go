@@ -0,0 +1,21 @@
+package interpret
+
+import "interpreter/ast"
+
+// User defined interface
+// Methods are dispatched on the class of the instance, so interfaces are only types
+type Interface struct {
+	decl *ast.InterfaceDeclaration // Declaration of interface
+}
+
+func (i *Interface) Name() string {
+	return i.decl.Name
+}
+
+func (i *Interface) Type() {}
+
+func NewInterface(decl *ast.InterfaceDeclaration) *Interface {
+	return &Interface{
+		decl: decl,
+	}
+}
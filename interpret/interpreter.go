@@ -4,33 +4,105 @@ import (
 	"fmt"
 	"interpreter/ast"
 	"interpreter/token"
+	"interpreter/types"
 	"math"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 type Interpreter struct {
-	env *Environment
+	file     string // Name of file being executed
+	env      *Environment
+	frames   []frame                 // Active calls, outermost first
+	types    map[ast.Expr]types.Type // Types of expressions found by typechecker
+	Overflow Overflow                // Behavior of integer arithmetic on overflow
 }
 
-func NewInterpreter() *Interpreter {
-	return &Interpreter{
-		env: NewEnvironment(),
+func NewInterpreter(file string, types map[ast.Expr]types.Type) *Interpreter {
+	i := &Interpreter{
+		file:  file,
+		env:   NewEnvironment(),
+		types: types,
 	}
+
+	return i
 }
 
-func (i *Interpreter) Visit(program []ast.Stmt) {
+// Execute program
+// Returns the runtime error that stopped execution, if any
+func (i *Interpreter) Visit(program []ast.Stmt) (err error) {
+	var current ast.Stmt
+	defer func() {
+		if r := recover(); r != nil {
+			runtimeErr, ok := r.(*RuntimeError)
+			if !ok {
+				// Failures of the interpreter itself are reported at the statement being executed
+				// so they never take down the host
+				runtimeErr = &RuntimeError{
+					File:    i.file,
+					Message: fmt.Sprintf("Internal error: %v", r),
+				}
+
+				if current != nil {
+					runtimeErr.Pos = current.Position()
+				}
+			}
+			err = runtimeErr
+		}
+	}()
+
 	i.collectTypesAndFunctions(program)
 
 	for _, s := range program {
+		current = s
 		i.executeStmt(s)
 	}
+
+	return nil
 }
 
+// Stop execution with runtime error at node
+func (i *Interpreter) error(message string, node ast.Node) {
+	panic(&RuntimeError{
+		File:    i.file,
+		Pos:     node.Position(),
+		Message: message,
+		Trace:   i.trace(node.Position()),
+	})
+}
+
+// Define all top level classes and functions before executing program
+// so they can be used before their declaration
 func (i *Interpreter) collectTypesAndFunctions(program []ast.Stmt) {
-	for range program {
+	for _, s := range program {
+		switch decl := s.(type) {
+		case *ast.ClassDeclaration:
+			i.defineClass(decl)
+		case *ast.EnumDeclaration:
+			i.defineEnum(decl)
+		case *ast.InterfaceDeclaration:
+			i.env.defineType(decl.Name, NewInterface(decl))
+		case *ast.FunDeclaration:
+			i.env.define(decl.Name, NewFunction(decl, i.env, i.file))
+		}
 	}
 }
 
+// Define class as type and as constructor
+func (i *Interpreter) defineClass(decl *ast.ClassDeclaration) {
+	class := NewClass(decl, i.env, i.file)
+	i.env.defineType(decl.Name, class)
+	i.env.define(decl.Name, class)
+}
+
+// Define enum as type and as value giving access to variants
+func (i *Interpreter) defineEnum(decl *ast.EnumDeclaration) {
+	enum := NewEnum(decl)
+	i.env.defineType(decl.Name, enum)
+	i.env.define(decl.Name, enum)
+}
+
 // Execute statements
 func (i *Interpreter) executeStmt(node ast.Stmt) {
 	switch stmt := node.(type) {
@@ -42,22 +114,166 @@ func (i *Interpreter) executeStmt(node ast.Stmt) {
 		i.executeVarDeclaration(stmt)
 	case *ast.AssignmentStmt:
 		i.executeAssignment(stmt)
+	case *ast.IndexAssignmentStmt:
+		i.executeIndexAssignment(stmt)
 	case *ast.IfStmt:
 		i.executeIfStmt(stmt)
 	case *ast.WhileStmt:
 		i.executeWhileStmt(stmt)
+	case *ast.ForStmt:
+		i.executeForStmt(stmt)
+	case *ast.BreakStmt:
+		panic(&breakLoop{label: stmt.Label})
+	case *ast.ContinueStmt:
+		panic(&continueLoop{label: stmt.Label})
+	case *ast.FunDeclaration:
+		i.env.define(stmt.Name, NewFunction(stmt, i.env, i.file))
+	case *ast.ReturnStmt:
+		i.executeReturnStmt(stmt)
+	case *ast.ThrowStmt:
+		i.executeThrowStmt(stmt)
+	case *ast.ClassDeclaration:
+		i.defineClass(stmt)
+	case *ast.EnumDeclaration:
+		i.defineEnum(stmt)
+	case *ast.InterfaceDeclaration:
+		i.env.defineType(stmt.Name, NewInterface(stmt))
+	case *ast.FieldAssignmentStmt:
+		i.executeFieldAssignment(stmt)
 	default:
-		panic(fmt.Sprintf("unexpected ast.Stmt: %#v", stmt))
+		i.error("Cannot execute statement", stmt)
+	}
+}
+
+// Execute return statement
+// Unwinds to the enclosing function call
+func (i *Interpreter) executeReturnStmt(stmt *ast.ReturnStmt) {
+	var v Value = NewUnit()
+	if stmt.Value != nil {
+		v = i.evaluateExpr(stmt.Value)
 	}
+
+	panic(&returnValue{value: v})
+}
+
+// Execute throw statement
+// Unwinds to the closest try expression catching the value
+func (i *Interpreter) executeThrowStmt(stmt *ast.ThrowStmt) {
+	v := i.evaluateExpr(stmt.Value)
+
+	// Rethrown runtime errors keep their position and stack trace
+	if e, ok := v.(*Exception); ok {
+		panic(e.err)
+	}
+
+	instance := v.(*Instance)
+	message := i.call(instance.method("message").(*Function), []Value{}, stmt).(*String)
+	panic(&RuntimeError{
+		File:    i.file,
+		Pos:     stmt.Pos,
+		Message: fmt.Sprintf("Uncaught %s: %s", instance.Name(), message.Value),
+		Trace:   i.trace(stmt.Pos),
+		value:   v,
+	})
 }
 
 // Execute while statement
 func (i *Interpreter) executeWhileStmt(stmt *ast.WhileStmt) {
 	for i.evaluateExpr(stmt.Condition).(*Boolean).Value {
-		i.executeBlockStmt(stmt.Block)
+		if !i.executeIteration(stmt.Label, func() { i.executeBlockStmt(stmt.Block) }) {
+			return
+		}
 	}
 }
 
+// Execute single iteration of loop with label
+// Returns false if the loop was exited with break
+func (i *Interpreter) executeIteration(label string, body func()) (proceed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch signal := r.(type) {
+			case *breakLoop:
+				if !targets(signal.label, label) {
+					panic(r)
+				}
+				proceed = false
+			case *continueLoop:
+				if !targets(signal.label, label) {
+					panic(r)
+				}
+				proceed = true
+			default:
+				panic(r)
+			}
+		}
+	}()
+
+	body()
+	return true
+}
+
+// Execute for statement
+func (i *Interpreter) executeForStmt(stmt *ast.ForStmt) {
+	switch iterable := i.evaluateExpr(stmt.Iterable).(type) {
+	case *Range:
+		for n, ok := iterable.Start, iterable.contains(iterable.Start); ok; n, ok = iterable.next(n) {
+			if !i.executeLoopBody(stmt, NewInteger(n)) {
+				return
+			}
+		}
+	case *String:
+		for _, c := range iterable.Value {
+			if !i.executeLoopBody(stmt, NewChar(c)) {
+				return
+			}
+		}
+	case *List:
+		for _, v := range iterable.Elements {
+			if !i.executeLoopBody(stmt, v) {
+				return
+			}
+		}
+	case *Map:
+		// Iterate over a copy of the keys so the map can be modified in the loop
+		keys := append([]Value{}, iterable.keys...)
+		for _, k := range keys {
+			v, ok := iterable.get(k)
+			if !ok {
+				continue
+			}
+
+			if !i.executeMapLoopBody(stmt, k, v) {
+				return
+			}
+		}
+	default:
+		i.error(fmt.Sprintf("Cannot iterate over %s", iterable.Name()), stmt.Iterable)
+	}
+}
+
+// Execute body of for loop with a fresh binding of the loop variable
+// Returns false if the loop was exited with break
+func (i *Interpreter) executeLoopBody(stmt *ast.ForStmt, v Value) bool {
+	i.enterBlock()
+	defer i.exitBlock()
+
+	i.env.define(stmt.Name, v)
+	return i.executeIteration(stmt.Label, func() { i.executeBlockStmt(stmt.Block) })
+}
+
+// Execute body of for loop over map entry
+// Returns false if the loop was exited with break
+func (i *Interpreter) executeMapLoopBody(stmt *ast.ForStmt, key Value, v Value) bool {
+	i.enterBlock()
+	defer i.exitBlock()
+
+	i.env.define(stmt.Name, key)
+	if stmt.Value != "" {
+		i.env.define(stmt.Value, v)
+	}
+	return i.executeIteration(stmt.Label, func() { i.executeBlockStmt(stmt.Block) })
+}
+
 // Execute if statement
 func (i *Interpreter) executeIfStmt(stmt *ast.IfStmt) {
 	cond := i.evaluateExpr(stmt.Condition).(*Boolean)
@@ -86,23 +302,95 @@ func (i *Interpreter) executeVarDeclaration(stmt *ast.VarDeclaration) {
 	if stmt.Value != nil {
 		v = i.evaluateExpr(stmt.Value)
 	} else {
-		type_string := stmt.Type.Value
-		t := i.env.lookupType(type_string)
-		switch t.(type) {
+		v = i.zeroValue(stmt.Type)
+	}
+
+	i.env.define(stmt.Name, v)
+}
+
+// Get initial value of variable declared without a value
+func (i *Interpreter) zeroValue(t ast.TypeExpr) Value {
+	switch n := t.(type) {
+	case *ast.NamedType:
+		// Generic types like List<int> have no zero value
+		if len(n.Args) != 0 {
+			return nil
+		}
+
+		switch t := i.env.lookupType(n.Name).(type) {
 		case *Inbuilt:
-			v = getInbuiltValue(t.(*Inbuilt))
+			return getInbuiltValue(t)
+		case *Class, *Enum, *Interface, nil:
+			// Typechecker guarantees the variable is assigned before use
+			// Type parameters are not defined at runtime
+			return nil
 		default:
-			panic(fmt.Sprintf("unexpected interpret.Type: %#v", t))
+			i.error(fmt.Sprintf("Type %s has no zero value", n.Name), n)
+			return nil
 		}
+	default:
+		// Typechecker guarantees the variable is assigned before use
+		return nil
 	}
-
-	i.env.define(stmt.Name, v)
 }
 
 // Execute assignment
 func (i *Interpreter) executeAssignment(stmt *ast.AssignmentStmt) {
 	v := i.evaluateExpr(stmt.Value)
-	i.env.assign(stmt.Name, v)
+
+	// Compound assignment applies the operator to the current value
+	if op, ok := token.CompoundOperator(stmt.Op.Kind); ok {
+		v = i.binaryOp(op, i.variable(stmt.Name, stmt), v, stmt)
+	}
+
+	// Functions can assign variables declared after they are called
+	if !i.env.assign(stmt.Name, v) {
+		i.error(fmt.Sprintf("Identifier assigned before declared: %s", stmt.Name), stmt)
+	}
+}
+
+// Execute assignment to field of instance
+func (i *Interpreter) executeFieldAssignment(stmt *ast.FieldAssignmentStmt) {
+	instance := i.evaluateExpr(stmt.Object).(*Instance)
+	v := i.evaluateExpr(stmt.Value)
+
+	// Compound assignment applies the operator to the current value
+	if op, ok := token.CompoundOperator(stmt.Op.Kind); ok {
+		v = i.binaryOp(op, instance.fields[stmt.Name], v, stmt)
+	}
+
+	instance.fields[stmt.Name] = v
+}
+
+// Execute assignment to list element or map entry
+func (i *Interpreter) executeIndexAssignment(stmt *ast.IndexAssignmentStmt) {
+	object := i.evaluateExpr(stmt.Object)
+	index := i.evaluateExpr(stmt.Index)
+
+	switch target := object.(type) {
+	case *List:
+		n := index.(*Integer).Value
+		i.checkBounds(n, len(target.Elements), stmt)
+
+		v := i.evaluateExpr(stmt.Value)
+
+		// Compound assignment applies the operator to the current element
+		if op, ok := token.CompoundOperator(stmt.Op.Kind); ok {
+			v = i.binaryOp(op, target.Elements[n], v, stmt)
+		}
+
+		target.Elements[n] = v
+	case *Map:
+		v := i.evaluateExpr(stmt.Value)
+
+		if op, ok := token.CompoundOperator(stmt.Op.Kind); ok {
+			v = i.binaryOp(op, i.lookupKey(target, index, stmt), v, stmt)
+		}
+
+		target.set(index, v)
+	default:
+		i.error(fmt.Sprintf("Cannot assign to element of %s", object.Name()), stmt)
+	}
 }
 
 // Evaluate expressions
@@ -122,11 +410,399 @@ func (i *Interpreter) evaluateExpr(node ast.Expr) Value {
 		return i.evaluateBlockExpr(n)
 	case *ast.IfExpr:
 		return i.evaluateIfExpr(n)
+	case *ast.TryExpr:
+		return i.evaluateTryExpr(n)
+	case *ast.UnwrapExpr:
+		return i.evaluateUnwrapExpr(n)
 	case *ast.LogicalExpr:
 		return i.evaluateLogicalExpr(n)
+	case *ast.CallExpr:
+		return i.evaluateCallExpr(n)
+	case *ast.LambdaExpr:
+		return NewLambda(n, i.env, i.file)
+	case *ast.RangeExpr:
+		return i.evaluateRangeExpr(n)
+	case *ast.MatchExpr:
+		return i.evaluateMatchExpr(n)
+	case *ast.ListLiteral:
+		return i.evaluateListLiteral(n)
+	case *ast.MapLiteral:
+		return i.evaluateMapLiteral(n)
+	case *ast.IndexExpr:
+		return i.evaluateIndexExpr(n)
+	case *ast.GetExpr:
+		return i.evaluateGetExpr(n)
+	case *ast.ThisExpr:
+		return i.env.lookup("this")
+	case *ast.ElvisExpr:
+		return i.evaluateElvisExpr(n)
+	case *ast.NonNullExpr:
+		return i.evaluateNonNullExpr(n)
+	case *ast.IsExpr:
+		return NewBoolean(i.instanceOf(i.evaluateExpr(n.Expr), n.Type))
+	case *ast.CastExpr:
+		return i.evaluateCastExpr(n)
+	default:
+		i.error("Cannot evaluate expression", n)
+		return nil
+	}
+}
+
+// Evaluate list literal
+func (i *Interpreter) evaluateListLiteral(expr *ast.ListLiteral) Value {
+	elements := make([]Value, len(expr.Elements))
+	for n, element := range expr.Elements {
+		elements[n] = i.evaluateExpr(element)
+	}
+
+	return NewList(elements)
+}
+
+// Evaluate map literal
+// Later entries replace earlier entries with the same key
+func (i *Interpreter) evaluateMapLiteral(expr *ast.MapLiteral) Value {
+	m := NewMap()
+	for n := range expr.Keys {
+		m.set(i.evaluateExpr(expr.Keys[n]), i.evaluateExpr(expr.Values[n]))
+	}
+
+	return m
+}
+
+// Evaluate indexing of lists, maps and strings
+func (i *Interpreter) evaluateIndexExpr(expr *ast.IndexExpr) Value {
+	object := i.evaluateExpr(expr.Object)
+	index := i.evaluateExpr(expr.Index)
+
+	switch v := object.(type) {
+	case *List:
+		n := index.(*Integer).Value
+		i.checkBounds(n, len(v.Elements), expr)
+		return v.Elements[n]
+	case *Map:
+		return i.lookupKey(v, index, expr)
+	case *String:
+		n := index.(*Integer).Value
+		chars := []rune(v.Value)
+		i.checkBounds(n, len(chars), expr)
+		return NewChar(chars[n])
 	default:
-		panic(fmt.Sprintf("unexpected ast.Expr: %#v", n))
+		i.error(fmt.Sprintf("Cannot index %s", object.Name()), expr)
+		return nil
+	}
+}
+
+// Report runtime error if index is outside of 0..<length
+func (i *Interpreter) checkBounds(index int, length int, node ast.Node) {
+	if index < 0 || index >= length {
+		i.error(fmt.Sprintf("Index %d out of bounds for length %d", index, length), node)
+	}
+}
+
+// Get value of key in map
+// Reports runtime error if key is not present
+func (i *Interpreter) lookupKey(m *Map, key Value, node ast.Node) Value {
+	v, ok := m.get(key)
+	if !ok {
+		i.error(fmt.Sprintf("Key not found: %s", formatValue(key)), node)
+	}
+
+	return v
+}
+
+// Evaluate member access
+func (i *Interpreter) evaluateGetExpr(expr *ast.GetExpr) Value {
+	object := i.evaluateExpr(expr.Object)
+
+	// Safe access on null gives null
+	if expr.Safe && isNull(object) {
+		return object
+	}
+
+	switch v := object.(type) {
+	case *Enum:
+		decl := v.variant(expr.Name)
+		if len(decl.Fields) == 0 {
+			return NewVariant(v, decl, nil)
+		}
+
+		return NewBuiltin(decl.Name, func(_ *Interpreter, args []Value, _ ast.Node) Value {
+			return NewVariant(v, decl, args)
+		})
+	case *Instance:
+		if field, ok := v.fields[expr.Name]; ok {
+			return field
+		}
+
+		if method := v.method(expr.Name); method != nil {
+			return method
+		}
+
+		if v.class.decl.Data && expr.Name == "copy" {
+			return &Copy{instance: v}
+		}
+	case *List:
+		if expr.Name == "length" {
+			return NewInteger(len(v.Elements))
+		}
+	case *Map:
+		switch expr.Name {
+		case "length":
+			return NewInteger(len(v.keys))
+		case "contains":
+			return NewBuiltin(expr.Name, func(_ *Interpreter, args []Value, _ ast.Node) Value {
+				_, ok := v.get(args[0])
+				return NewBoolean(ok)
+			})
+		case "remove":
+			return NewBuiltin(expr.Name, func(_ *Interpreter, args []Value, _ ast.Node) Value {
+				v.remove(args[0])
+				return NewUnit()
+			})
+		}
+	case *String:
+		if expr.Name == "length" {
+			return NewInteger(len([]rune(v.Value)))
+		}
+	case *Result:
+		switch expr.Name {
+		case "isOk":
+			return NewBuiltin(expr.Name, func(_ *Interpreter, args []Value, _ ast.Node) Value {
+				return NewBoolean(v.Ok)
+			})
+		case "isErr":
+			return NewBuiltin(expr.Name, func(_ *Interpreter, args []Value, _ ast.Node) Value {
+				return NewBoolean(!v.Ok)
+			})
+		case "unwrapOr":
+			return NewBuiltin(expr.Name, func(_ *Interpreter, args []Value, _ ast.Node) Value {
+				if v.Ok {
+					return v.Value
+				}
+				return args[0]
+			})
+		}
+	case *Exception:
+		if expr.Name == "message" {
+			return NewBuiltin(expr.Name, func(_ *Interpreter, args []Value, _ ast.Node) Value {
+				return NewString(v.err.Message)
+			})
+		}
+	case *Integer, *Real, *BigInt, *Decimal:
+		if target, ok := conversions[expr.Name]; ok {
+			return NewBuiltin(expr.Name, func(i *Interpreter, args []Value, call ast.Node) Value {
+				return i.convert(v, target, call)
+			})
+		}
+	}
+
+	// Primitives are converted to strings as they are printed
+	if expr.Name == "toString" {
+		return NewBuiltin(expr.Name, func(_ *Interpreter, args []Value, _ ast.Node) Value {
+			return NewString(formatValue(object))
+		})
+	}
+
+	i.error(fmt.Sprintf("%s has no member %s", object.Name(), expr.Name), expr)
+	return nil
+}
+
+// Evaluate range expressions
+func (i *Interpreter) evaluateRangeExpr(expr *ast.RangeExpr) Value {
+	start := i.evaluateExpr(expr.Start).(*Integer)
+	end := i.evaluateExpr(expr.End).(*Integer)
+
+	step := 1
+	if expr.Step != nil {
+		step = i.evaluateExpr(expr.Step).(*Integer).Value
+		if step <= 0 {
+			i.error(fmt.Sprintf("Step must be positive, was %d", step), expr.Step)
+		}
+	}
+
+	return NewRange(start.Value, end.Value, step, expr.Inclusive)
+}
+
+// Evaluate match expressions
+// Value of the first matching arm is returned
+func (i *Interpreter) evaluateMatchExpr(expr *ast.MatchExpr) Value {
+	subject := i.evaluateExpr(expr.Subject)
+
+	for n := range expr.Arms {
+		if v, ok := i.evaluateMatchArm(expr.Arms[n:], subject); ok {
+			return v
+		}
+	}
+
+	i.error(fmt.Sprintf("No arm matched value %s", formatValue(subject)), expr)
+	return nil
+}
+
+// Evaluate first of arms if its pattern matches subject
+// Arms ending in 'fall' continue into the body of the following arm
+func (i *Interpreter) evaluateMatchArm(arms []*ast.MatchArm, subject Value) (Value, bool) {
+	i.enterBlock()
+	defer i.exitBlock()
+
+	arm := arms[0]
+	if !i.matchPattern(arm.Pattern, subject) {
+		return nil, false
+	}
+
+	if arm.Guard != nil && !i.evaluateExpr(arm.Guard).(*Boolean).Value {
+		return nil, false
+	}
+
+	n := 0
+	for arms[n].Fall {
+		n++
 	}
+
+	return i.evaluateExpr(arms[n].Body), true
+}
+
+// Check if pattern matches value
+// Bindings are defined in the current environment
+func (i *Interpreter) matchPattern(pattern ast.Pattern, v Value) bool {
+	switch p := pattern.(type) {
+	case *ast.WildcardPattern:
+		return true
+	case *ast.BindingPattern:
+		i.env.define(p.Name, v)
+		return true
+	case *ast.LiteralPattern:
+		return equals(i.evaluateLiteralExpr(p.Value), v)
+	case *ast.RangePattern:
+		start := i.evaluateLiteralExpr(p.Start)
+		end := i.evaluateLiteralExpr(p.End)
+		return inRange(v, start, end, p.Inclusive)
+	case *ast.VariantPattern:
+		if r, ok := v.(*Result); ok {
+			return r.variant() == p.Variant && i.matchPattern(p.Fields[0], r.Value)
+		}
+
+		variant := v.(*Variant)
+		if variant.decl.Name != p.Variant {
+			return false
+		}
+
+		for n, field := range p.Fields {
+			if !i.matchPattern(field, variant.fields[n]) {
+				return false
+			}
+		}
+		return true
+	default:
+		i.error("Cannot match pattern", p)
+		return false
+	}
+}
+
+// Evaluate function calls
+func (i *Interpreter) evaluateCallExpr(expr *ast.CallExpr) Value {
+	callee := i.evaluateExpr(expr.Callee)
+
+	// Method accessed with '?.' on null is not called
+	if isNull(callee) {
+		return callee
+	}
+
+	args := make([]Value, len(expr.Args))
+	for n, arg := range expr.Args {
+		args[n] = i.evaluateExpr(arg)
+	}
+
+	switch f := callee.(type) {
+	case *Function:
+		return i.call(f, args, expr)
+	case *Lambda:
+		return i.callLambda(f, args, expr)
+	case *Builtin:
+		return f.fn(i, args, expr)
+	case *Class:
+		return i.construct(f, args)
+	case *Copy:
+		return i.copy(f.instance, expr.Labels, args)
+	default:
+		i.error(fmt.Sprintf("Cannot call %s", callee.Name()), expr)
+		return nil
+	}
+}
+
+// Create instance of class with fields initialized from constructor arguments
+func (i *Interpreter) construct(class *Class, args []Value) Value {
+	fields := map[string]Value{}
+	for n, field := range class.decl.Fields {
+		fields[field.Name] = args[n]
+	}
+
+	return NewInstance(class, fields)
+}
+
+// Create copy of data class instance
+// Positional arguments replace fields in order, named arguments replace the named field
+func (i *Interpreter) copy(instance *Instance, labels []string, args []Value) Value {
+	fields := map[string]Value{}
+	for name, v := range instance.fields {
+		fields[name] = v
+	}
+
+	for n, arg := range args {
+		name := labels[n]
+		if name == "" {
+			name = instance.class.decl.Fields[n].Name
+		}
+		fields[name] = arg
+	}
+
+	return NewInstance(instance.class, fields)
+}
+
+// Call lambda with arguments
+// Returns value of last expression in body
+func (i *Interpreter) callLambda(l *Lambda, args []Value, call ast.Node) Value {
+	i.enterFrame("<lambda>", l.file, call)
+	previous := i.env
+	i.env = NewEnvironmentWithParent(l.closure)
+	defer func() {
+		i.env = previous
+		i.exitFrame()
+	}()
+
+	for n, param := range l.expr.Params {
+		i.env.define(param.Name, args[n])
+	}
+
+	return i.evaluateBlockExpr(l.expr.Body)
+}
+
+// Call function with arguments
+// Body is executed in a new environment enclosed by the closure of the function
+func (i *Interpreter) call(f *Function, args []Value, call ast.Node) (result Value) {
+	i.enterFrame(f.decl.Name, f.file, call)
+	previous := i.env
+	i.env = NewEnvironmentWithParent(f.closure)
+
+	defer func() {
+		i.env = previous
+		i.exitFrame()
+
+		if r := recover(); r != nil {
+			ret, ok := r.(*returnValue)
+			if !ok {
+				panic(r)
+			}
+			result = ret.value
+		}
+	}()
+
+	for n, param := range f.decl.Params {
+		i.env.define(param.Name, args[n])
+	}
+
+	i.executeBlockStmt(f.decl.Body)
+
+	return NewUnit()
 }
 
 // Evaluate logical expressions
@@ -157,28 +833,78 @@ func (i *Interpreter) evaluateIfExpr(expr *ast.IfExpr) Value {
 	}
 }
 
+// Evaluate try expression
+// Errors raised in the body are handled by the first catch clause accepting them
+// The finally block runs however the expression is left
+func (i *Interpreter) evaluateTryExpr(expr *ast.TryExpr) Value {
+	if expr.Finally != nil {
+		defer i.evaluateBlockExpr(expr.Finally)
+	}
+
+	v, err := i.attempt(expr.Body)
+	if err == nil {
+		return v
+	}
+
+	caught := err.caught()
+	for _, catch := range expr.Catches {
+		if !i.instanceOf(caught, catch.Type) {
+			continue
+		}
+
+		i.enterBlock()
+		defer i.exitBlock()
+		i.env.define(catch.Name, caught)
+		return i.evaluateBlockExpr(catch.Body)
+	}
+
+	panic(err)
+}
+
+// Evaluate unwrapping of result with '?'
+// Errors are returned from the enclosing function
+func (i *Interpreter) evaluateUnwrapExpr(expr *ast.UnwrapExpr) Value {
+	r := i.evaluateExpr(expr.Expr).(*Result)
+	if !r.Ok {
+		panic(&returnValue{value: r})
+	}
+
+	return r.Value
+}
+
+// Evaluate block, recovering from runtime errors raised in it
+// Other ways of leaving the block, like return and break, are not stopped
+func (i *Interpreter) attempt(block *ast.BlockExpr) (v Value, err *RuntimeError) {
+	defer func() {
+		if r := recover(); r != nil {
+			runtimeErr, ok := r.(*RuntimeError)
+			if !ok {
+				panic(r)
+			}
+			err = runtimeErr
+		}
+	}()
+
+	return i.evaluateBlockExpr(block), nil
+}
+
 // Evaluate block expressions
 func (i *Interpreter) evaluateBlockExpr(expr *ast.BlockExpr) Value {
 	i.enterBlock()
 	defer i.exitBlock()
 
-	var val Value
+	// Blocks without a trailing expression evaluate to unit
+	var val Value = NewUnit()
 	for n, stmt := range expr.Stmts {
 		switch s := stmt.(type) {
-		case *ast.AssignmentStmt:
-			i.executeAssignment(s)
-		case *ast.BlockStmt:
-			i.executeBlockStmt(s)
 		case *ast.ExprStmt:
 			if n == len(expr.Stmts)-1 {
 				val = i.evaluateExpr(s.Expr)
 			} else {
 				i.evaluateExpr(s.Expr)
 			}
-		case *ast.VarDeclaration:
-			i.executeVarDeclaration(s)
 		default:
-			panic(fmt.Sprintf("unexpected ast.Stmt: %#v", s))
+			i.executeStmt(s)
 		}
 	}
 
@@ -187,7 +913,18 @@ func (i *Interpreter) evaluateBlockExpr(expr *ast.BlockExpr) Value {
 
 // Evaluate identfiers expression
 func (i *Interpreter) evaluateIdent(expr *ast.Ident) Value {
-	return i.env.lookup(expr.Name)
+	return i.variable(expr.Name, expr)
+}
+
+// Get value of variable with name
+// Functions can be called before the variables they read are declared or assigned
+func (i *Interpreter) variable(name string, node ast.Node) Value {
+	v := i.env.lookup(name)
+	if v == nil {
+		i.error(fmt.Sprintf("Identifier used before initialized: %s", name), node)
+	}
+
+	return v
 }
 
 // Evaluate literal expressions
@@ -195,21 +932,57 @@ func (i *Interpreter) evaluateLiteralExpr(expr *ast.LiteralExpr) Value {
 	switch expr.Kind {
 	case token.CHAR:
 		return NewChar(rune(expr.Value[0]))
-	case token.REAL:
-		float, _ := strconv.ParseFloat(expr.Value, 64)
-		return NewReal(float)
+	case token.INTEGER, token.REAL, token.BIGINT, token.DECIMAL:
+		return i.numberLiteral(expr, expr.Value)
 	case token.STRING:
 		return NewString(expr.Value)
-	case token.INTEGER:
-		integer, _ := strconv.ParseInt(expr.Value, 10, 32)
-		return NewInteger(int(integer))
 	case token.TRUE:
 		return NewBoolean(true)
 	case token.FALSE:
 		return NewBoolean(false)
+	case token.NULL:
+		return NewNull()
 	default:
-		panic(fmt.Sprintf("Unexpected token.TokenType: %#v", expr.Kind))
+		i.error(fmt.Sprintf("Invalid literal %s", expr.Value), expr)
+		return nil
+	}
+}
+
+// Evaluate number literal with the type found by typechecker
+// Value includes the sign of negated literals, which may only fit in their type when negated
+func (i *Interpreter) numberLiteral(expr *ast.LiteralExpr, value string) Value {
+	var name string
+	switch expr.Kind {
+	case token.INTEGER:
+		name = "int"
+	case token.REAL:
+		name = "real"
+	case token.BIGINT:
+		name = "bigint"
+	case token.DECIMAL:
+		name = "decimal"
+	}
+
+	if t, ok := i.types[expr]; ok {
+		name = t.Name()
 	}
+
+	switch name {
+	case "bigint":
+		integer, _ := new(big.Int).SetString(value, 10)
+		return NewBigInt(integer)
+	case "decimal":
+		decimal, _ := new(big.Rat).SetString(value)
+		return NewDecimal(decimal)
+	}
+
+	if expr.Kind == token.REAL {
+		float, _ := strconv.ParseFloat(value, 64)
+		return newFloat(float, name == "f32")
+	}
+
+	integer, _ := new(big.Int).SetString(value, 10)
+	return wrap(integer, intKinds[name])
 }
 
 // Evaluate unary expressions
@@ -220,20 +993,152 @@ func (i *Interpreter) evaluateUnaryExpr(expr *ast.UnaryExpr) Value {
 		val := i.evaluateExpr(right).(*Boolean)
 		return NewBoolean(!val.Value)
 	case token.MINUS:
+		if literal, ok := right.(*ast.LiteralExpr); ok && token.IsNumber(literal.Kind) {
+			return i.numberLiteral(literal, "-"+literal.Value)
+		}
+
 		val := i.evaluateExpr(right)
 		switch v := val.(type) {
 		case *Integer:
-			return NewInteger(-v.Value)
+			return i.fit(new(big.Int).Neg(v.big()), v.Kind, expr)
 		case *Real:
-			return NewReal(-v.Value)
+			return v.with(-v.Value)
+		case *BigInt:
+			return NewBigInt(new(big.Int).Neg(v.Value))
+		case *Decimal:
+			return NewDecimal(new(big.Rat).Neg(v.Value))
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", v))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", expr.Op.Kind, v.Name()), expr)
+			return nil
 		}
 	case token.TILDE:
-		val := i.evaluateExpr(right).(*Integer)
-		return NewInteger(^val.Value)
+		switch v := i.evaluateExpr(right).(type) {
+		case *Integer:
+			return wrap(new(big.Int).Not(v.big()), v.Kind)
+		case *BigInt:
+			return NewBigInt(new(big.Int).Not(v.Value))
+		default:
+			i.error(fmt.Sprintf("Operator %s not defined for %s", expr.Op.Kind, v.Name()), expr)
+			return nil
+		}
+	default:
+		i.error(fmt.Sprintf("Unknown operator %s", expr.Op.Kind), expr)
+		return nil
+	}
+}
+
+// Evaluate elvis expression
+// Right operand is only evaluated if left operand is null
+func (i *Interpreter) evaluateElvisExpr(expr *ast.ElvisExpr) Value {
+	left := i.evaluateExpr(expr.Left)
+	if !isNull(left) {
+		return left
+	}
+
+	return i.evaluateExpr(expr.Right)
+}
+
+// Evaluate non-null assertion
+func (i *Interpreter) evaluateNonNullExpr(expr *ast.NonNullExpr) Value {
+	v := i.evaluateExpr(expr.Expr)
+	if isNull(v) {
+		i.error("Non-null assertion failed, value is null", expr)
+	}
+
+	return v
+}
+
+// Evaluate cast to other type
+// Safe casts give null where other casts fail
+func (i *Interpreter) evaluateCastExpr(expr *ast.CastExpr) Value {
+	v := i.evaluateExpr(expr.Expr)
+
+	// Numbers are converted if both types are numeric
+	if target, ok := i.numericCast(expr); ok {
+		if expr.Safe && !convertible(v, target) {
+			return NewNull()
+		}
+
+		return i.convert(v, target, expr)
+	}
+
+	// Typechecker guarantees casts to types that cannot be checked at runtime always succeed
+	if !i.checkable(expr.Type) || i.instanceOf(v, expr.Type) {
+		return v
+	}
+
+	if expr.Safe {
+		return NewNull()
+	}
+
+	i.error(fmt.Sprintf("Cannot cast value of type %s to %s", v.Name(), i.types[expr].Name()), expr)
+	return nil
+}
+
+// Get name of numeric type converted to by cast, if the cast converts between numbers
+func (i *Interpreter) numericCast(expr *ast.CastExpr) (string, bool) {
+	from, ok := i.types[expr.Expr].(*types.Primitive)
+	if !ok || !isNumeric(from.Name()) {
+		return "", false
+	}
+
+	named, ok := expr.Type.(*ast.NamedType)
+	if !ok {
+		return "", false
+	}
+
+	to, ok := i.env.lookupType(named.Name).(*Inbuilt)
+	if !ok || !isNumeric(to.Name()) {
+		return "", false
+	}
+
+	return to.Name(), true
+}
+
+// Check if values can be checked for type at runtime
+// Type parameters and types with type arguments cannot be checked
+func (i *Interpreter) checkable(t ast.TypeExpr) bool {
+	switch n := t.(type) {
+	case *ast.NullableType:
+		return i.checkable(n.Elem)
+	case *ast.NamedType:
+		return len(n.Args) == 0 && i.env.lookupType(n.Name) != nil
+	default:
+		return false
+	}
+}
+
+// Check if value has type at runtime
+// Typechecker guarantees the type can be checked, i.e. it has no type arguments
+func (i *Interpreter) instanceOf(v Value, t ast.TypeExpr) bool {
+	switch n := t.(type) {
+	case *ast.NullableType:
+		return isNull(v) || i.instanceOf(v, n.Elem)
+	case *ast.NamedType:
+		switch t := i.env.lookupType(n.Name).(type) {
+		case *Inbuilt:
+			return v.Name() == t.Name()
+		case *Class:
+			instance, ok := v.(*Instance)
+			return ok && instance.class == t
+		case *Enum:
+			variant, ok := v.(*Variant)
+			return ok && variant.enum == t
+		case *Interface:
+			// Runtime errors are caught as errors
+			if _, ok := v.(*Exception); ok {
+				return t == errorInterface
+			}
+
+			instance, ok := v.(*Instance)
+			return ok && instance.class.implements(t)
+		default:
+			i.error(fmt.Sprintf("Cannot check type %s at runtime", n.Name), n)
+			return false
+		}
 	default:
-		panic(fmt.Sprintf("unexpected token.TokenType: %#v", expr.Op.Kind))
+		i.error("Cannot check type at runtime", t)
+		return false
 	}
 }
 
@@ -241,74 +1146,123 @@ func (i *Interpreter) evaluateUnaryExpr(expr *ast.UnaryExpr) Value {
 func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 	left := i.evaluateExpr(expr.Left)
 	right := i.evaluateExpr(expr.Right)
-	switch expr.Op.Kind {
+	return i.binaryOp(expr.Op.Kind, left, right, expr)
+}
+
+// Apply binary operator to operands
+// Operands have the same type, integer results are fitted to their kind
+func (i *Interpreter) binaryOp(op token.TokenType, left Value, right Value, node ast.Node) Value {
+	switch op {
 	case token.PLUS:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(l.Value + r.Value)
+			return i.integerOp(op, l, r, node)
 		case *Real:
 			r := right.(*Real)
-			return NewReal(l.Value + r.Value)
+			return l.with(l.Value + r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
+		case *Decimal:
+			r := right.(*Decimal)
+			return i.decimalOp(op, l, r, node)
 		case *String:
 			r := right.(*String)
 			return NewString(l.Value + r.Value)
 		default:
-			panic(fmt.Sprintf("Unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.MINUS:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(l.Value - r.Value)
+			return i.integerOp(op, l, r, node)
 		case *Real:
 			r := right.(*Real)
-			return NewReal(l.Value - r.Value)
+			return l.with(l.Value - r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
+		case *Decimal:
+			r := right.(*Decimal)
+			return i.decimalOp(op, l, r, node)
 		default:
-			panic(fmt.Sprintf("Unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.SLASH:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(l.Value / r.Value)
+			return i.integerOp(op, l, r, node)
 		case *Real:
 			r := right.(*Real)
-			return NewReal(l.Value / r.Value)
+			return l.with(l.Value / r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
+		case *Decimal:
+			r := right.(*Decimal)
+			return i.decimalOp(op, l, r, node)
 		default:
-			panic(fmt.Sprintf("Unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.STAR:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(l.Value * r.Value)
+			return i.integerOp(op, l, r, node)
 		case *Real:
 			r := right.(*Real)
-			return NewReal(l.Value * r.Value)
+			return l.with(l.Value * r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
+		case *Decimal:
+			r := right.(*Decimal)
+			return i.decimalOp(op, l, r, node)
 		default:
-			panic(fmt.Sprintf("Unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.STAR_STAR:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(intPow(l.Value, r.Value))
+			return i.integerOp(op, l, r, node)
 		case *Real:
 			r := right.(*Real)
-			return NewReal(math.Pow(l.Value, r.Value))
+			return l.with(math.Pow(l.Value, r.Value))
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
+		case *Decimal:
+			r := right.(*Decimal)
+			return i.decimalOp(op, l, r, node)
 		default:
-			panic(fmt.Sprintf("Unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.PERCENT:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(modulo(l.Value, r.Value))
+			return i.integerOp(op, l, r, node)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.EQUAL_EQUAL:
+		if isNull(left) || isNull(right) {
+			return NewBoolean(equals(left, right))
+		}
+
 		switch l := left.(type) {
 		case *Boolean:
 			r := right.(*Boolean)
@@ -322,13 +1276,27 @@ func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 		case *Real:
 			r := right.(*Real)
 			return NewBoolean(l.Value == r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return NewBoolean(l.Value.Cmp(r.Value) == 0)
+		case *Decimal:
+			r := right.(*Decimal)
+			return NewBoolean(l.Value.Cmp(r.Value) == 0)
 		case *String:
 			r := right.(*String)
 			return NewBoolean(l.Value == r.Value)
+		case *Instance, *Variant, *List, *Map, *Result, *Range, *Unit, *Function, *Lambda, *Builtin, *Copy, *Exception:
+			// Only reachable through values of type parameters, except for data classes, enums, lists, maps and results
+			return NewBoolean(equals(l, right))
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.BANG_EQUAL:
+		if isNull(left) || isNull(right) {
+			return NewBoolean(!equals(left, right))
+		}
+
 		switch l := left.(type) {
 		case *Boolean:
 			r := right.(*Boolean)
@@ -342,12 +1310,20 @@ func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 		case *Real:
 			r := right.(*Real)
 			return NewBoolean(l.Value != r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return NewBoolean(l.Value.Cmp(r.Value) != 0)
+		case *Decimal:
+			r := right.(*Decimal)
+			return NewBoolean(l.Value.Cmp(r.Value) != 0)
 		case *String:
 			r := right.(*String)
 			return NewBoolean(l.Value != r.Value)
+		case *Instance, *Variant, *List, *Map, *Result, *Range, *Unit, *Function, *Lambda, *Builtin, *Copy, *Exception:
+			return NewBoolean(!equals(l, right))
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
-
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.GREATER:
 		switch l := left.(type) {
@@ -356,15 +1332,22 @@ func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 			return NewBoolean(l.Value > r.Value)
 		case *Integer:
 			r := right.(*Integer)
-			return NewBoolean(l.Value > r.Value)
+			return NewBoolean(compareIntegers(l, r) > 0)
 		case *Real:
 			r := right.(*Real)
 			return NewBoolean(l.Value > r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return NewBoolean(l.Value.Cmp(r.Value) > 0)
+		case *Decimal:
+			r := right.(*Decimal)
+			return NewBoolean(l.Value.Cmp(r.Value) > 0)
 		case *String:
 			r := right.(*String)
 			return NewBoolean(l.Value > r.Value)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.GREATER_EQUAL:
 		switch l := left.(type) {
@@ -373,15 +1356,22 @@ func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 			return NewBoolean(l.Value >= r.Value)
 		case *Integer:
 			r := right.(*Integer)
-			return NewBoolean(l.Value >= r.Value)
+			return NewBoolean(compareIntegers(l, r) >= 0)
 		case *Real:
 			r := right.(*Real)
 			return NewBoolean(l.Value >= r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return NewBoolean(l.Value.Cmp(r.Value) >= 0)
+		case *Decimal:
+			r := right.(*Decimal)
+			return NewBoolean(l.Value.Cmp(r.Value) >= 0)
 		case *String:
 			r := right.(*String)
 			return NewBoolean(l.Value >= r.Value)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.LESS:
 		switch l := left.(type) {
@@ -390,15 +1380,22 @@ func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 			return NewBoolean(l.Value < r.Value)
 		case *Integer:
 			r := right.(*Integer)
-			return NewBoolean(l.Value < r.Value)
+			return NewBoolean(compareIntegers(l, r) < 0)
 		case *Real:
 			r := right.(*Real)
 			return NewBoolean(l.Value < r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return NewBoolean(l.Value.Cmp(r.Value) < 0)
+		case *Decimal:
+			r := right.(*Decimal)
+			return NewBoolean(l.Value.Cmp(r.Value) < 0)
 		case *String:
 			r := right.(*String)
 			return NewBoolean(l.Value < r.Value)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.LESS_EQUAL:
 		switch l := left.(type) {
@@ -407,15 +1404,22 @@ func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 			return NewBoolean(l.Value <= r.Value)
 		case *Integer:
 			r := right.(*Integer)
-			return NewBoolean(l.Value <= r.Value)
+			return NewBoolean(compareIntegers(l, r) <= 0)
 		case *Real:
 			r := right.(*Real)
 			return NewBoolean(l.Value <= r.Value)
+		case *BigInt:
+			r := right.(*BigInt)
+			return NewBoolean(l.Value.Cmp(r.Value) <= 0)
+		case *Decimal:
+			r := right.(*Decimal)
+			return NewBoolean(l.Value.Cmp(r.Value) <= 0)
 		case *String:
 			r := right.(*String)
 			return NewBoolean(l.Value <= r.Value)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.LAND:
 		switch l := left.(type) {
@@ -423,7 +1427,8 @@ func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 			r := right.(*Boolean)
 			return NewBoolean(l.Value && r.Value)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.LOR:
 		switch l := left.(type) {
@@ -431,34 +1436,61 @@ func (i *Interpreter) evaluateBinaryExpr(expr *ast.BinaryExpr) Value {
 			r := right.(*Boolean)
 			return NewBoolean(l.Value || r.Value)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.AND:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(l.Value & r.Value)
+			return i.integerOp(op, l, r, node)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.OR:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(l.Value | r.Value)
+			return i.integerOp(op, l, r, node)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	case token.CARET:
 		switch l := left.(type) {
 		case *Integer:
 			r := right.(*Integer)
-			return NewInteger(l.Value ^ r.Value)
+			return i.integerOp(op, l, r, node)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
+		default:
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
+		}
+	case token.TILDE:
+		// Bit clear, only reachable through '~='
+		switch l := left.(type) {
+		case *Integer:
+			r := right.(*Integer)
+			return i.integerOp(op, l, r, node)
+		case *BigInt:
+			r := right.(*BigInt)
+			return i.bigIntOp(op, l, r, node)
 		default:
-			panic(fmt.Sprintf("unexpected Value: %#v", l))
+			i.error(fmt.Sprintf("Operator %s not defined for %s", op, l.Name()), node)
+			return nil
 		}
 	default:
-		panic(fmt.Sprintf("Unexpected binary operator: %#v", expr.Op.Kind))
+		i.error(fmt.Sprintf("Unknown operator %s", op), node)
+		return nil
 	}
 }
 
@@ -471,17 +1503,95 @@ func (i *Interpreter) exitBlock() {
 }
 
 func (i *Interpreter) printValue(val Value) {
+	// Unit values are not printed
+	if _, ok := val.(*Unit); ok {
+		return
+	}
+
+	fmt.Println(formatValue(val))
+}
+
+// Format value for printing
+func formatValue(val Value) string {
 	switch v := val.(type) {
 	case *Boolean:
-		fmt.Printf("%v\n", v.Value)
+		return fmt.Sprintf("%v", v.Value)
 	case *Char:
-		fmt.Printf("%c\n", v.Value)
+		return fmt.Sprintf("%c", v.Value)
 	case *Integer:
-		fmt.Printf("%d\n", v.Value)
+		if v.Kind == U64 {
+			return strconv.FormatUint(uint64(v.Value), 10)
+		}
+
+		return fmt.Sprintf("%d", v.Value)
 	case *Real:
-		fmt.Printf("%f\n", v.Value)
+		return fmt.Sprintf("%f", v.Value)
+	case *BigInt:
+		return v.Value.String()
+	case *Decimal:
+		return formatDecimal(v.Value)
 	case *String:
-		fmt.Printf("%s\n", v.Value)
+		return v.Value
+	case *Function:
+		return fmt.Sprintf("<fun %s>", v.decl.Name)
+	case *Lambda:
+		return "<lambda>"
+	case *Builtin:
+		return fmt.Sprintf("<builtin %s>", v.name)
+	case *Copy:
+		return "<builtin copy>"
+	case *Class:
+		return fmt.Sprintf("<class %s>", v.Name())
+	case *Enum:
+		return fmt.Sprintf("<enum %s>", v.Name())
+	case *Variant:
+		if len(v.fields) == 0 {
+			return v.decl.Name
+		}
+
+		fields := make([]string, len(v.fields))
+		for n, field := range v.decl.Fields {
+			fields[n] = fmt.Sprintf("%s=%s", field.Name, formatValue(v.fields[n]))
+		}
+		return fmt.Sprintf("%s(%s)", v.decl.Name, strings.Join(fields, ", "))
+	case *Instance:
+		if !v.class.decl.Data {
+			return fmt.Sprintf("<%s>", v.Name())
+		}
+
+		// Data classes print their fields, e.g. Point(x=1, y=2)
+		fields := make([]string, len(v.class.decl.Fields))
+		for n, field := range v.class.decl.Fields {
+			fields[n] = fmt.Sprintf("%s=%s", field.Name, formatValue(v.fields[field.Name]))
+		}
+		return fmt.Sprintf("%s(%s)", v.Name(), strings.Join(fields, ", "))
+	case *Range:
+		return v.String()
+	case *List:
+		elements := make([]string, len(v.Elements))
+		for n, element := range v.Elements {
+			elements[n] = formatValue(element)
+		}
+		return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+	case *Map:
+		if len(v.keys) == 0 {
+			return "[:]"
+		}
+
+		entries := make([]string, len(v.keys))
+		for n, k := range v.keys {
+			value, _ := v.get(k)
+			entries[n] = fmt.Sprintf("%s: %s", formatValue(k), formatValue(value))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(entries, ", "))
+	case *Result:
+		return fmt.Sprintf("%s(%s)", v.variant(), formatValue(v.Value))
+	case *Exception:
+		return fmt.Sprintf("<%s: %s>", v.Name(), v.err.Message)
+	case *Unit:
+		return "()"
+	case *Null:
+		return "null"
 	default:
 		panic(fmt.Sprintf("unexpected Value: %#v", val))
 	}
@@ -0,0 +1,19 @@
+package interpret
+
+// List of values
+// Lists are shared by reference
+type List struct {
+	Elements []Value
+}
+
+func (l *List) Name() string {
+	return "list"
+}
+
+func (l *List) value() {}
+
+func NewList(elements []Value) *List {
+	return &List{
+		Elements: elements,
+	}
+}
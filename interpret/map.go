@@ -0,0 +1,74 @@
+package interpret
+
+import "fmt"
+
+// Map from keys to values
+// Entries are kept in insertion order, and maps are shared by reference
+type Map struct {
+	keys    []Value       // Keys in insertion order
+	entries map[any]Value // Values by hash of key
+}
+
+func (m *Map) Name() string {
+	return "map"
+}
+
+func (m *Map) value() {}
+
+func NewMap() *Map {
+	return &Map{
+		keys:    []Value{},
+		entries: map[any]Value{},
+	}
+}
+
+// Get value of key
+func (m *Map) get(key Value) (Value, bool) {
+	v, ok := m.entries[hash(key)]
+	return v, ok
+}
+
+// Set value of key, adding key if not present
+func (m *Map) set(key Value, v Value) {
+	h := hash(key)
+	if _, ok := m.entries[h]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.entries[h] = v
+}
+
+// Remove key from map
+func (m *Map) remove(key Value) {
+	h := hash(key)
+	if _, ok := m.entries[h]; !ok {
+		return
+	}
+
+	delete(m.entries, h)
+	for n, k := range m.keys {
+		if hash(k) == h {
+			m.keys = append(m.keys[:n], m.keys[n+1:]...)
+			break
+		}
+	}
+}
+
+// Get Go value of key usable as key in Go map
+// Typechecker guarantees keys are hashable primitives
+func hash(key Value) any {
+	switch k := key.(type) {
+	case *Integer:
+		return k.Value
+	case *BigInt:
+		return k.Value.String()
+	case *Char:
+		return k.Value
+	case *String:
+		return k.Value
+	case *Boolean:
+		return k.Value
+	default:
+		panic(fmt.Sprintf("unexpected map key: %#v", key))
+	}
+}
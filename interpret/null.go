@@ -0,0 +1,20 @@
+package interpret
+
+// Value of the 'null' literal
+type Null struct{}
+
+func (n *Null) Name() string {
+	return "null"
+}
+
+func (n *Null) value() {}
+
+func NewNull() Value {
+	return &Null{}
+}
+
+// Check if value is null
+func isNull(v Value) bool {
+	_, ok := v.(*Null)
+	return ok
+}
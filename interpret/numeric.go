@@ -0,0 +1,557 @@
+package interpret
+
+import (
+	"cmp"
+	"fmt"
+	"interpreter/ast"
+	"interpreter/token"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+)
+
+// Behavior of integer arithmetic when the result does not fit in its type
+type Overflow int
+
+const (
+	Wrapping Overflow = iota // Result wraps around, keeping its lowest bits
+	Checked                  // Result outside the range of its type is a runtime error
+)
+
+// Size and signedness of integer
+type IntKind int
+
+const (
+	I64 IntKind = iota // int
+	I8
+	I16
+	I32
+	U8
+	U16
+	U32
+	U64
+)
+
+// Integer kinds by name of their type
+var intKinds = map[string]IntKind{
+	"int": I64,
+	"i8":  I8,
+	"i16": I16,
+	"i32": I32,
+	"u8":  U8,
+	"u16": U16,
+	"u32": U32,
+	"u64": U64,
+}
+
+// Types converted to by conversion methods of numbers, by name of method
+var conversions = map[string]string{
+	"toI8":      "i8",
+	"toI16":     "i16",
+	"toI32":     "i32",
+	"toI64":     "int",
+	"toU8":      "u8",
+	"toU16":     "u16",
+	"toU32":     "u32",
+	"toU64":     "u64",
+	"toF32":     "f32",
+	"toF64":     "real",
+	"toInt":     "int",
+	"toReal":    "real",
+	"toBigInt":  "bigint",
+	"toDecimal": "decimal",
+}
+
+func (k IntKind) String() string {
+	for name, kind := range intKinds {
+		if kind == k {
+			return name
+		}
+	}
+
+	return "illegal"
+}
+
+// Number of bits used by integers of kind
+func (k IntKind) bits() uint {
+	switch k {
+	case I8, U8:
+		return 8
+	case I16, U16:
+		return 16
+	case I32, U32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// Check if result of word operation on integers of kind is in the range of kind
+func (k IntKind) holds(n uint64) bool {
+	switch k {
+	case I64, U64:
+		return true
+	case U8, U16, U32:
+		return n < 1<<k.bits()
+	default:
+		limit := int64(1) << (k.bits() - 1)
+		return int64(n) >= -limit && int64(n) < limit
+	}
+}
+
+// Check if value is in the range of kind
+func (k IntKind) contains(v *big.Int) bool {
+	limit := new(big.Int).Lsh(big.NewInt(1), k.bits())
+	switch k {
+	case U8, U16, U32, U64:
+		return v.Sign() >= 0 && v.Cmp(limit) < 0
+	default:
+		limit.Rsh(limit, 1)
+		return v.Cmp(new(big.Int).Neg(limit)) >= 0 && v.Cmp(limit) < 0
+	}
+}
+
+// Get exact value of integer
+func (n *Integer) big() *big.Int {
+	if n.Kind == U64 {
+		return new(big.Int).SetUint64(uint64(n.Value))
+	}
+
+	return big.NewInt(int64(n.Value))
+}
+
+// Compare integers of the same kind
+// Returns -1, 0 or 1 if left is less than, equal to or greater than right
+func compareIntegers(left *Integer, right *Integer) int {
+	if left.Kind == U64 {
+		return cmp.Compare(uint64(left.Value), uint64(right.Value))
+	}
+
+	return cmp.Compare(left.Value, right.Value)
+}
+
+// Create integer of kind from its lowest bits
+// Signed kinds use two's complement
+func wrap(v *big.Int, kind IntKind) *Integer {
+	modulus := new(big.Int).Lsh(big.NewInt(1), kind.bits())
+	return wrapBits(new(big.Int).Mod(v, modulus).Uint64(), kind)
+}
+
+// Create integer of kind from the lowest bits of a word
+func wrapBits(bits uint64, kind IntKind) *Integer {
+	var n int
+	switch kind {
+	case I8:
+		n = int(int8(bits))
+	case I16:
+		n = int(int16(bits))
+	case I32:
+		n = int(int32(bits))
+	case U8:
+		n = int(uint8(bits))
+	case U16:
+		n = int(uint16(bits))
+	case U32:
+		n = int(uint32(bits))
+	default:
+		n = int(bits)
+	}
+
+	return &Integer{
+		Value: n,
+		Kind:  kind,
+	}
+}
+
+// Create integer of kind from exact value
+// Values outside the range of the kind wrap around, or stop execution if overflow is checked
+func (i *Interpreter) fit(v *big.Int, kind IntKind, node ast.Node) Value {
+	if !kind.contains(v) && i.Overflow == Checked {
+		i.error(fmt.Sprintf("Integer overflow, %s does not fit in %s", v, kind), node)
+	}
+
+	return wrap(v, kind)
+}
+
+// Apply arithmetic or bitwise operator to integers of the same kind
+// Results are computed on words, and only computed exactly to report overflow
+func (i *Interpreter) integerOp(op token.TokenType, left *Integer, right *Integer, node ast.Node) Value {
+	result, overflow := i.wordOp(op, left, right, node)
+	if i.Overflow == Checked && (overflow || !left.Kind.holds(result)) {
+		return i.exactOp(op, left, right, node)
+	}
+
+	return wrapBits(result, left.Kind)
+}
+
+// Apply operator to integers as 64 bit words, which are unsigned for u64 and signed otherwise
+// Overflow reports results that may not fit in a word, whose lowest bits are still returned
+func (i *Interpreter) wordOp(op token.TokenType, left *Integer, right *Integer, node ast.Node) (result uint64, overflow bool) {
+	l, r := uint64(left.Value), uint64(right.Value)
+	unsigned := left.Kind == U64
+	switch op {
+	case token.PLUS:
+		return addWord(l, r, unsigned)
+	case token.MINUS:
+		result, borrow := bits.Sub64(l, r, 0)
+		if unsigned {
+			return result, borrow != 0
+		}
+		return result, int64((l^r)&(l^result)) < 0
+	case token.STAR:
+		return mulWord(l, r, unsigned)
+	case token.SLASH:
+		i.checkDivisor(r == 0, node)
+		if unsigned {
+			return l / r, false
+		}
+		return uint64(int64(l) / int64(r)), int64(l) == math.MinInt64 && int64(r) == -1
+	case token.PERCENT:
+		i.checkDivisor(r == 0, node)
+		if unsigned {
+			return l % r, false
+		}
+
+		rem := int64(l) % int64(r)
+		if rem < 0 {
+			return addWord(uint64(rem), r, false)
+		}
+		return uint64(rem), false
+	case token.STAR_STAR:
+		// Non-positive exponents give 1, other powers are computed by squaring
+		if !unsigned && int64(r) <= 0 {
+			return 1, false
+		}
+
+		result, base := uint64(1), l
+		for exp := r; exp > 0; exp >>= 1 {
+			var o bool
+			if exp&1 == 1 {
+				result, o = mulWord(result, base, unsigned)
+				overflow = overflow || o
+			}
+
+			if exp > 1 {
+				base, o = mulWord(base, base, unsigned)
+				overflow = overflow || o
+			}
+		}
+		return result, overflow
+	case token.AND:
+		return l & r, false
+	case token.OR:
+		return l | r, false
+	case token.CARET:
+		return l ^ r, false
+	case token.TILDE:
+		return l &^ r, false
+	default:
+		i.error(fmt.Sprintf("Operator %s not defined for %s", op, left.Name()), node)
+		return 0, false
+	}
+}
+
+// Add words, reporting if the sum does not fit in a word
+func addWord(l uint64, r uint64, unsigned bool) (uint64, bool) {
+	result, carry := bits.Add64(l, r, 0)
+	if unsigned {
+		return result, carry != 0
+	}
+
+	return result, int64((l^result)&(r^result)) < 0
+}
+
+// Multiply words, reporting if the product does not fit in a word
+// Signed products are computed from the magnitudes of the factors
+func mulWord(l uint64, r uint64, unsigned bool) (uint64, bool) {
+	if unsigned {
+		hi, lo := bits.Mul64(l, r)
+		return lo, hi != 0
+	}
+
+	abs := func(n uint64) uint64 {
+		if int64(n) < 0 {
+			return -n
+		}
+		return n
+	}
+
+	// Negative products may be one further from zero than positive ones
+	hi, lo := bits.Mul64(abs(l), abs(r))
+	limit := uint64(math.MaxInt64)
+	if int64(l^r) < 0 {
+		limit++
+	}
+
+	return l * r, hi != 0 || lo > limit
+}
+
+// Apply arithmetic or bitwise operator to exact values of integers of the same kind
+// Values outside the range of the kind wrap around, or stop execution if overflow is checked
+func (i *Interpreter) exactOp(op token.TokenType, left *Integer, right *Integer, node ast.Node) Value {
+	l, r := left.big(), right.big()
+	result, exact := i.bigOp(op, l, r, 64, node)
+	if !exact && i.Overflow == Checked {
+		i.error(fmt.Sprintf("Integer overflow, %s ** %s does not fit in %s", l, r, left.Kind), node)
+	}
+
+	return i.fit(result, left.Kind, node)
+}
+
+// Apply arithmetic or bitwise operator to bigints, which never overflow
+func (i *Interpreter) bigIntOp(op token.TokenType, left *BigInt, right *BigInt, node ast.Node) Value {
+	result, _ := i.bigOp(op, left.Value, right.Value, 0, node)
+	return NewBigInt(result)
+}
+
+// Apply arithmetic or bitwise operator to exact integers
+// Powers needing more than limit bits are only computed modulo 2^limit, which is reported by exact being false
+// A limit of 0 always computes the exact result
+func (i *Interpreter) bigOp(op token.TokenType, l *big.Int, r *big.Int, limit uint, node ast.Node) (result *big.Int, exact bool) {
+	result = new(big.Int)
+	switch op {
+	case token.PLUS:
+		result.Add(l, r)
+	case token.MINUS:
+		result.Sub(l, r)
+	case token.STAR:
+		result.Mul(l, r)
+	case token.SLASH:
+		i.checkDivisor(r.Sign() == 0, node)
+		result.Quo(l, r)
+	case token.PERCENT:
+		i.checkDivisor(r.Sign() == 0, node)
+		result = modulo(l, r)
+	case token.STAR_STAR:
+		if limit == 0 && !powFits(l, r) {
+			i.error(fmt.Sprintf("Power too large, result of ** would have more than %d bits", maxPowBits), node)
+		}
+		return intPow(l, r, limit)
+	case token.AND:
+		result.And(l, r)
+	case token.OR:
+		result.Or(l, r)
+	case token.CARET:
+		result.Xor(l, r)
+	case token.TILDE:
+		result.AndNot(l, r)
+	default:
+		i.error(fmt.Sprintf("Operator %s not defined for integers", op), node)
+		return nil, false
+	}
+
+	return result, true
+}
+
+// Apply arithmetic operator to decimals
+// Results are exact, powers must have integer exponents
+func (i *Interpreter) decimalOp(op token.TokenType, left *Decimal, right *Decimal, node ast.Node) Value {
+	l, r := left.Value, right.Value
+	result := new(big.Rat)
+	switch op {
+	case token.PLUS:
+		result.Add(l, r)
+	case token.MINUS:
+		result.Sub(l, r)
+	case token.STAR:
+		result.Mul(l, r)
+	case token.SLASH:
+		i.checkDivisor(r.Sign() == 0, node)
+		result.Quo(l, r)
+	case token.STAR_STAR:
+		if !r.IsInt() {
+			i.error(fmt.Sprintf("Exponent of decimal must be an integer, was %s", formatDecimal(r)), node)
+		}
+
+		exp := new(big.Int).Abs(r.Num())
+		if !powFits(l.Num(), exp) || !powFits(l.Denom(), exp) {
+			i.error(fmt.Sprintf("Power too large, result of ** would have more than %d bits", maxPowBits), node)
+		}
+		num, _ := intPow(l.Num(), exp, 0)
+		denom, _ := intPow(l.Denom(), exp, 0)
+
+		// Negative exponents give the reciprocal
+		if r.Sign() < 0 {
+			i.checkDivisor(l.Sign() == 0, node)
+			num, denom = denom, num
+		}
+		result.SetFrac(num, denom)
+	default:
+		i.error(fmt.Sprintf("Operator %s not defined for decimal", op), node)
+		return nil
+	}
+
+	return NewDecimal(result)
+}
+
+// Report runtime error on division by zero
+func (i *Interpreter) checkDivisor(zero bool, node ast.Node) {
+	if zero {
+		i.error("Division by zero", node)
+	}
+}
+
+// Format decimal with as many digits as needed to be exact
+// Decimals with infinite expansions, e.g. 1/3, are rounded to 28 digits after the point
+func formatDecimal(d *big.Rat) string {
+	// Expansion is finite if the denominator only has factors 2 and 5
+	denom := new(big.Int).Set(d.Denom())
+	twos, fives := 0, 0
+	for denom.Bit(0) == 0 {
+		denom.Rsh(denom, 1)
+		twos++
+	}
+
+	five := big.NewInt(5)
+	mod := new(big.Int)
+	for {
+		quo, _ := new(big.Int).QuoRem(denom, five, mod)
+		if mod.Sign() != 0 {
+			break
+		}
+		denom = quo
+		fives++
+	}
+
+	digits := min(max(twos, fives, 1), 28)
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		digits = 28
+	}
+
+	return d.FloatString(digits)
+}
+
+// Create real, rounded to single precision if needed
+func newFloat(v float64, single bool) Value {
+	if single {
+		v = float64(float32(v))
+	}
+
+	return &Real{
+		Value:  v,
+		Single: single,
+	}
+}
+
+// Create real of the same precision as r
+func (r *Real) with(v float64) Value {
+	return newFloat(v, r.Single)
+}
+
+// Convert number to numeric type with name
+// Numbers are truncated towards zero when converted to integers
+func (i *Interpreter) convert(v Value, target string, node ast.Node) Value {
+	switch target {
+	case "real", "f32":
+		return newFloat(toFloat(v), target == "f32")
+	case "decimal":
+		return NewDecimal(i.toRat(v, target, node))
+	}
+
+	// Integers of any kind are converted from their exact value
+	exact, ok := integerPart(v)
+	if !ok {
+		i.error(fmt.Sprintf("Cannot convert %s to %s", formatValue(v), target), node)
+	}
+
+	if target == "bigint" {
+		return NewBigInt(exact)
+	}
+
+	return i.fit(exact, intKinds[target], node)
+}
+
+// Check if type with name is numeric
+func isNumeric(name string) bool {
+	_, ok := intKinds[name]
+	return ok || name == "real" || name == "f32" || name == "bigint" || name == "decimal"
+}
+
+// Check if number can be converted to numeric type with name without leaving its range
+// Integer targets must hold the integer part, which NaN and infinity do not have
+func convertible(v Value, target string) bool {
+	switch target {
+	case "real", "f32":
+		return true
+	case "decimal":
+		r, ok := v.(*Real)
+		return !ok || !math.IsNaN(r.Value) && !math.IsInf(r.Value, 0)
+	}
+
+	exact, ok := integerPart(v)
+	return ok && (target == "bigint" || intKinds[target].contains(exact))
+}
+
+// Get exact integer part of number, truncated towards zero
+// Returns false for NaN and infinity
+func integerPart(v Value) (*big.Int, bool) {
+	switch n := v.(type) {
+	case *Integer:
+		return n.big(), true
+	case *BigInt:
+		return n.Value, true
+	case *Real:
+		if math.IsNaN(n.Value) || math.IsInf(n.Value, 0) {
+			return nil, false
+		}
+
+		exact, _ := big.NewFloat(n.Value).Int(nil)
+		return exact, true
+	case *Decimal:
+		return new(big.Int).Quo(n.Value.Num(), n.Value.Denom()), true
+	default:
+		panic(fmt.Sprintf("unexpected Value: %#v", v))
+	}
+}
+
+// Get nearest real of number
+func toFloat(v Value) float64 {
+	switch n := v.(type) {
+	case *Integer:
+		f, _ := new(big.Float).SetInt(n.big()).Float64()
+		return f
+	case *BigInt:
+		f, _ := new(big.Float).SetInt(n.Value).Float64()
+		return f
+	case *Real:
+		return n.Value
+	case *Decimal:
+		f, _ := n.Value.Float64()
+		return f
+	default:
+		panic(fmt.Sprintf("unexpected Value: %#v", v))
+	}
+}
+
+// Get exact value of number as fraction
+// Reals are converted from their shortest decimal representation, so 0.1 is exactly 1/10
+func (i *Interpreter) toRat(v Value, target string, node ast.Node) *big.Rat {
+	switch n := v.(type) {
+	case *Integer:
+		return new(big.Rat).SetInt(n.big())
+	case *BigInt:
+		return new(big.Rat).SetInt(n.Value)
+	case *Real:
+		i.checkFinite(n, target, node)
+
+		size := 64
+		if n.Single {
+			size = 32
+		}
+		r, _ := new(big.Rat).SetString(strconv.FormatFloat(n.Value, 'g', -1, size))
+		return r
+	case *Decimal:
+		return n.Value
+	default:
+		i.error(fmt.Sprintf("Cannot convert %s to %s", v.Name(), target), node)
+		return nil
+	}
+}
+
+// Report runtime error if real is NaN or infinite, which cannot be converted to target
+func (i *Interpreter) checkFinite(r *Real, target string, node ast.Node) {
+	if math.IsNaN(r.Value) || math.IsInf(r.Value, 0) {
+		i.error(fmt.Sprintf("Cannot convert %v to %s", r.Value, target), node)
+	}
+}
@@ -0,0 +1,192 @@
+package interpret
+
+import (
+	"interpreter/ast"
+	"interpreter/token"
+	"math"
+	"testing"
+)
+
+func TestHolds(t *testing.T) {
+	tests := []struct {
+		kind  IntKind
+		value uint64
+		holds bool
+	}{
+		{I8, 127, true},
+		{I8, 128, false},
+		{I8, uint64(0xffffffffffffff80), true}, // -128
+		{I8, uint64(0xffffffffffffff7f), false},
+		{U8, 255, true},
+		{U8, 256, false},
+		{U8, math.MaxUint64, false},
+		{I32, math.MaxInt32, true},
+		{I32, math.MaxInt32 + 1, false},
+		{I64, math.MaxUint64, true},
+		{U64, math.MaxUint64, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.kind.holds(tt.value); got != tt.holds {
+			t.Errorf("%s.holds(%#x) = %v, expected %v", tt.kind, tt.value, got, tt.holds)
+		}
+	}
+}
+
+func TestAddWord(t *testing.T) {
+	tests := []struct {
+		l, r     uint64
+		unsigned bool
+		result   uint64
+		overflow bool
+	}{
+		{1, 2, false, 3, false},
+		{math.MaxInt64, 1, false, 1 << 63, true},
+		{1 << 63, 1 << 63, false, 0, true},   // MinInt64 + MinInt64
+		{math.MaxUint64, 1, false, 0, false}, // -1 + 1
+		{math.MaxUint64, 1, true, 0, true},
+		{math.MaxInt64, 1, true, 1 << 63, false},
+	}
+
+	for _, tt := range tests {
+		result, overflow := addWord(tt.l, tt.r, tt.unsigned)
+		if result != tt.result || overflow != tt.overflow {
+			t.Errorf("addWord(%#x, %#x, %v) = (%#x, %v), expected (%#x, %v)",
+				tt.l, tt.r, tt.unsigned, result, overflow, tt.result, tt.overflow)
+		}
+	}
+}
+
+func TestMulWord(t *testing.T) {
+	minInt := uint64(1) << 63
+	negOne := uint64(math.MaxUint64)
+
+	tests := []struct {
+		l, r     uint64
+		unsigned bool
+		result   uint64
+		overflow bool
+	}{
+		{6, 7, false, 42, false},
+		{minInt, 1, false, minInt, false},
+		{minInt, negOne, false, minInt, true},
+		{1 << 62, 2, false, minInt, true},
+		{1 << 62, negOne - 1, false, minInt, false}, // 2^62 * -2 is MinInt64
+		{1 << 32, 1 << 32, true, 0, true},
+		{1 << 32, 1<<32 - 1, true, 1<<64 - 1<<32, false},
+	}
+
+	for _, tt := range tests {
+		result, overflow := mulWord(tt.l, tt.r, tt.unsigned)
+		if result != tt.result || overflow != tt.overflow {
+			t.Errorf("mulWord(%#x, %#x, %v) = (%#x, %v), expected (%#x, %v)",
+				tt.l, tt.r, tt.unsigned, result, overflow, tt.result, tt.overflow)
+		}
+	}
+}
+
+func TestIntegerOp(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       token.TokenType
+		kind     IntKind
+		l, r     int
+		wrapped  int  // Result when wrapping
+		overflow bool // Whether checked arithmetic reports overflow, otherwise the result is the wrapped one
+	}{
+		{"MinInt64 / -1", token.SLASH, I64, math.MinInt64, -1, math.MinInt64, true},
+		{"MinInt64 % -1", token.PERCENT, I64, math.MinInt64, -1, 0, false},
+		{"MinInt64 * -1", token.STAR, I64, math.MinInt64, -1, math.MinInt64, true},
+		{"MaxInt64 + 1", token.PLUS, I64, math.MaxInt64, 1, math.MinInt64, true},
+		{"-7 % 3", token.PERCENT, I64, -7, 3, 2, false},
+		{"u64 max + 1", token.PLUS, U64, -1, 1, 0, true},
+		{"u64 0 - 1", token.MINUS, U64, 0, 1, -1, true},
+		{"u64 2^32 * 2^32", token.STAR, U64, 1 << 32, 1 << 32, 0, true},
+		{"u64 max / 2", token.SLASH, U64, -1, 2, math.MaxInt64, false},
+		{"i8 127 + 1", token.PLUS, I8, 127, 1, -128, true},
+		{"i8 -128 - 1", token.MINUS, I8, -128, 1, 127, true},
+		{"i8 -128 * -1", token.STAR, I8, -128, -1, -128, true},
+		{"i8 -128 / -1", token.SLASH, I8, -128, -1, -128, true},
+		{"u8 200 + 100", token.PLUS, U8, 200, 100, 44, true},
+		{"i8 2 ** 7", token.STAR_STAR, I8, 2, 7, -128, true},
+		{"i8 -2 ** 7", token.STAR_STAR, I8, -2, 7, -128, false},
+		{"i8 2 ** 6", token.STAR_STAR, I8, 2, 6, 64, false},
+		{"2 ** 63", token.STAR_STAR, I64, 2, 63, math.MinInt64, true},
+		{"-2 ** 63", token.STAR_STAR, I64, -2, 63, math.MinInt64, false},
+		{"3 ** 41", token.STAR_STAR, I64, 3, 41, -420491770248316829, true},
+		{"2 ** -1", token.STAR_STAR, I64, 2, -1, 1, false},
+		{"u64 2 ** 63", token.STAR_STAR, U64, 2, 63, math.MinInt64, false},
+		{"u64 2 ** 64", token.STAR_STAR, U64, 2, 64, 0, true},
+	}
+
+	for _, tt := range tests {
+		left, right := &Integer{Value: tt.l, Kind: tt.kind}, &Integer{Value: tt.r, Kind: tt.kind}
+		node := &ast.Ident{Name: tt.name}
+
+		i := NewInterpreter("test", nil)
+		got, err := evaluate(func() Value { return i.integerOp(tt.op, left, right, node) })
+		if err != nil {
+			t.Errorf("%s: unexpected error when wrapping: %v", tt.name, err)
+		} else if got.(*Integer).Value != tt.wrapped {
+			t.Errorf("%s: wrapped to %d, expected %d", tt.name, got.(*Integer).Value, tt.wrapped)
+		}
+
+		i.Overflow = Checked
+		got, err = evaluate(func() Value { return i.integerOp(tt.op, left, right, node) })
+		if tt.overflow {
+			if err == nil {
+				t.Errorf("%s: expected overflow error when checked, found %d", tt.name, got.(*Integer).Value)
+			}
+		} else if err != nil {
+			t.Errorf("%s: unexpected error when checked: %v", tt.name, err)
+		} else if got.(*Integer).Value != tt.wrapped {
+			t.Errorf("%s: checked result %d, expected %d", tt.name, got.(*Integer).Value, tt.wrapped)
+		}
+	}
+}
+
+func TestNegateInteger(t *testing.T) {
+	tests := []struct {
+		kind     IntKind
+		value    int
+		wrapped  int
+		overflow bool
+	}{
+		{I8, -128, -128, true},
+		{I8, 127, -127, false},
+		{I64, math.MinInt64, math.MinInt64, true},
+		{U8, 1, 255, true},
+		{U8, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		i := NewInterpreter("test", nil)
+		i.env = NewEnvironmentWithParent(i.env)
+		i.env.define("x", &Integer{Value: tt.value, Kind: tt.kind})
+		expr := &ast.UnaryExpr{Op: token.Token{Kind: token.MINUS, Value: "-"}, Expr: &ast.Ident{Name: "x"}}
+
+		got, err := evaluate(func() Value { return i.evaluateExpr(expr) })
+		if err != nil {
+			t.Errorf("-(%d as %s): unexpected error when wrapping: %v", tt.value, tt.kind, err)
+		} else if got.(*Integer).Value != tt.wrapped {
+			t.Errorf("-(%d as %s): wrapped to %d, expected %d", tt.value, tt.kind, got.(*Integer).Value, tt.wrapped)
+		}
+
+		i.Overflow = Checked
+		_, err = evaluate(func() Value { return i.evaluateExpr(expr) })
+		if (err != nil) != tt.overflow {
+			t.Errorf("-(%d as %s): checked error %v, expected overflow %v", tt.value, tt.kind, err, tt.overflow)
+		}
+	}
+}
+
+// Evaluate value, returning the runtime error that stopped evaluation, if any
+func evaluate(f func() Value) (v Value, err *RuntimeError) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(*RuntimeError)
+		}
+	}()
+
+	return f(), nil
+}
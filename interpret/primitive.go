@@ -1,12 +1,26 @@
 package interpret
 
+import "math/big"
+
 // Primitive values
 type Integer struct {
 	Value int
+	Kind  IntKind // Size and signedness, values of u64 hold their bits
 }
 
 type Real struct {
-	Value float64
+	Value  float64
+	Single bool // Value of f32, rounded to single precision
+}
+
+// Arbitrary precision integer
+type BigInt struct {
+	Value *big.Int
+}
+
+// Exact decimal, stored as fraction so arithmetic never rounds
+type Decimal struct {
+	Value *big.Rat
 }
 
 type String struct {
@@ -21,15 +35,29 @@ type Boolean struct {
 	Value bool
 }
 
+type Unit struct{}
+
 // Implement Value interface for primitives
 func (i *Integer) Name() string {
-	return "int"
+	return i.Kind.String()
 }
 
 func (r *Real) Name() string {
+	if r.Single {
+		return "f32"
+	}
+
 	return "real"
 }
 
+func (b *BigInt) Name() string {
+	return "bigint"
+}
+
+func (d *Decimal) Name() string {
+	return "decimal"
+}
+
 func (s *String) Name() string {
 	return "string"
 }
@@ -42,11 +70,18 @@ func (b *Boolean) Name() string {
 	return "boolean"
 }
 
+func (u *Unit) Name() string {
+	return "unit"
+}
+
 func (i *Integer) value() {}
 func (r *Real) value()    {}
+func (b *BigInt) value()  {}
+func (d *Decimal) value() {}
 func (s *String) value()  {}
 func (c *Char) value()    {}
 func (b *Boolean) value() {}
+func (u *Unit) value()    {}
 
 // Constructors
 func NewChar(c rune) Value {
@@ -67,6 +102,18 @@ func NewReal(r float64) Value {
 	}
 }
 
+func NewBigInt(b *big.Int) Value {
+	return &BigInt{
+		Value: b,
+	}
+}
+
+func NewDecimal(d *big.Rat) Value {
+	return &Decimal{
+		Value: d,
+	}
+}
+
 func NewString(s string) Value {
 	return &String{
 		Value: s,
@@ -78,3 +125,7 @@ func NewBoolean(b bool) Value {
 		Value: b,
 	}
 }
+
+func NewUnit() Value {
+	return &Unit{}
+}
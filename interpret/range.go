@@ -0,0 +1,61 @@
+package interpret
+
+import (
+	"fmt"
+	"math"
+)
+
+// Range of integers
+type Range struct {
+	Start     int
+	End       int
+	Step      int
+	Inclusive bool
+}
+
+func (r *Range) Name() string {
+	return "range"
+}
+
+func (r *Range) value() {}
+
+func NewRange(start int, end int, step int, inclusive bool) Value {
+	return &Range{
+		Start:     start,
+		End:       end,
+		Step:      step,
+		Inclusive: inclusive,
+	}
+}
+
+// Check if n is within bounds of range
+func (r *Range) contains(n int) bool {
+	if r.Inclusive {
+		return n <= r.End
+	}
+
+	return n < r.End
+}
+
+// Get value following n in range
+// Returns false if there is none, also when stepping past n would overflow
+func (r *Range) next(n int) (int, bool) {
+	if n > math.MaxInt-r.Step {
+		return n, false
+	}
+
+	return n + r.Step, r.contains(n + r.Step)
+}
+
+func (r *Range) String() string {
+	op := "..<"
+	if r.Inclusive {
+		op = ".."
+	}
+
+	if r.Step != 1 {
+		return fmt.Sprintf("%d%s%d step %d", r.Start, op, r.End, r.Step)
+	}
+
+	return fmt.Sprintf("%d%s%d", r.Start, op, r.End)
+}
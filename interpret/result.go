@@ -0,0 +1,30 @@
+package interpret
+
+// Result of operation that can fail
+// Either Ok with a value, or Err with an error
+type Result struct {
+	Ok    bool  // Whether the operation succeeded
+	Value Value // Value of successful result, or error of failed result
+}
+
+func (r *Result) Name() string {
+	return "Result"
+}
+
+func (r *Result) value() {}
+
+func NewResult(ok bool, v Value) *Result {
+	return &Result{
+		Ok:    ok,
+		Value: v,
+	}
+}
+
+// Get name of variant of result
+func (r *Result) variant() string {
+	if r.Ok {
+		return "Ok"
+	}
+
+	return "Err"
+}
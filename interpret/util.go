@@ -1,26 +1,175 @@
 package interpret
 
-func intPow(left int, right int) int {
-	if right == 0 {
-		return 1
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// Largest number of bits in exact powers, larger ones take too long to compute and print
+const maxPowBits = 1 << 23
+
+// Raise integer to power, negative powers give 1
+// Results needing more than limit bits are only computed modulo 2^limit, which is reported by exact being false
+// A limit of 0 always computes the exact result
+func intPow(left *big.Int, right *big.Int, limit uint) (result *big.Int, exact bool) {
+	if right.Sign() <= 0 {
+		return big.NewInt(1), true
 	}
 
-	if right == 1 {
-		return left
+	// Powers of -1, 0 and 1 never grow, other bases need more than limit bits after limit multiplications
+	if limit == 0 || left.CmpAbs(big.NewInt(1)) <= 0 || right.Cmp(new(big.Int).SetUint64(uint64(limit))) <= 0 {
+		return new(big.Int).Exp(left, right, nil), true
 	}
 
-	result := 1
-	for range right {
-		result *= left
+	modulus := new(big.Int).Lsh(big.NewInt(1), limit)
+	return new(big.Int).Exp(left, right, modulus), false
+}
+
+// Check if exact power has at most maxPowBits bits
+// Powers of -1, 0 and 1 and non-positive powers never grow
+func powFits(left *big.Int, right *big.Int) bool {
+	if right.Sign() <= 0 || left.CmpAbs(big.NewInt(1)) <= 0 {
+		return true
 	}
-	return result
+
+	if !right.IsUint64() {
+		return false
+	}
+
+	// Every multiplication by the base adds at least one bit less than its length
+	hi, lo := bits.Mul64(uint64(left.BitLen()-1), right.Uint64())
+	return hi == 0 && lo <= maxPowBits
 }
 
-func modulo(left int, right int) int {
-	rem := left % right
-	if rem < 0 {
-		rem += right
+func modulo(left *big.Int, right *big.Int) *big.Int {
+	rem := new(big.Int).Rem(left, right)
+	if rem.Sign() < 0 {
+		rem.Add(rem, right)
 	}
 
 	return rem
 }
+
+// Check if two values of the same type are equal
+func equals(left Value, right Value) bool {
+	// Null is only equal to null
+	if isNull(left) || isNull(right) {
+		return isNull(left) && isNull(right)
+	}
+
+	switch l := left.(type) {
+	case *Boolean:
+		return l.Value == right.(*Boolean).Value
+	case *Char:
+		return l.Value == right.(*Char).Value
+	case *Integer:
+		return l.Value == right.(*Integer).Value
+	case *BigInt:
+		return l.Value.Cmp(right.(*BigInt).Value) == 0
+	case *Decimal:
+		return l.Value.Cmp(right.(*Decimal).Value) == 0
+	case *Real:
+		return l.Value == right.(*Real).Value
+	case *String:
+		return l.Value == right.(*String).Value
+	case *Unit:
+		return true
+	case *List:
+		r := right.(*List)
+		if len(l.Elements) != len(r.Elements) {
+			return false
+		}
+
+		for n := range l.Elements {
+			if !equals(l.Elements[n], r.Elements[n]) {
+				return false
+			}
+		}
+		return true
+	case *Map:
+		r := right.(*Map)
+		if len(l.keys) != len(r.keys) {
+			return false
+		}
+
+		for _, k := range l.keys {
+			lv, _ := l.get(k)
+			rv, ok := r.get(k)
+			if !ok || !equals(lv, rv) {
+				return false
+			}
+		}
+		return true
+	case *Instance:
+		// Instances of data classes are equal if they have the same class and equal fields
+		r, ok := right.(*Instance)
+		if !ok || l.class.decl != r.class.decl {
+			return false
+		}
+
+		if !l.class.decl.Data {
+			return l == r
+		}
+
+		for name, v := range l.fields {
+			if !equals(v, r.fields[name]) {
+				return false
+			}
+		}
+		return true
+	case *Variant:
+		r := right.(*Variant)
+		if l.decl != r.decl {
+			return false
+		}
+
+		for n := range l.fields {
+			if !equals(l.fields[n], r.fields[n]) {
+				return false
+			}
+		}
+		return true
+	case *Range:
+		return *l == *right.(*Range)
+	case *Result:
+		r := right.(*Result)
+		return l.Ok == r.Ok && equals(l.Value, r.Value)
+	case *Function, *Lambda, *Builtin, *Copy, *Exception:
+		return left == right
+	default:
+		panic(fmt.Sprintf("unexpected Value: %#v", l))
+	}
+}
+
+// Check if int or char value is between start and end
+func inRange(v Value, start Value, end Value, inclusive bool) bool {
+	var n, lower, upper int
+	switch x := v.(type) {
+	case *Integer:
+		// Integers are compared by kind, as values of u64 may not fit in int
+		from, to := compareIntegers(start.(*Integer), x), compareIntegers(x, end.(*Integer))
+		if inclusive {
+			return from <= 0 && to <= 0
+		}
+
+		return from <= 0 && to < 0
+	case *BigInt:
+		from, to := start.(*BigInt).Value.Cmp(x.Value), x.Value.Cmp(end.(*BigInt).Value)
+		if inclusive {
+			return from <= 0 && to <= 0
+		}
+
+		return from <= 0 && to < 0
+	case *Char:
+		n, lower, upper = int(x.Value), int(start.(*Char).Value), int(end.(*Char).Value)
+	default:
+		panic(fmt.Sprintf("unexpected Value: %#v", v))
+	}
+
+	if inclusive {
+		return lower <= n && n <= upper
+	}
+
+	return lower <= n && n < upper
+}
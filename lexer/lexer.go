@@ -1,8 +1,7 @@
 package lexer
 
 import (
-	"errors"
-	"fmt"
+	"interpreter/diagnostic"
 	"interpreter/token"
 	"strings"
 	"unicode"
@@ -21,11 +20,16 @@ type Lexer struct {
 
 // Create new lexer with source as text
 func NewLexer(source []byte, file string) *Lexer {
+	return NewLexerAt(source, file, 1)
+}
+
+// Create new lexer with source starting at row, e.g. for lines of the REPL
+func NewLexerAt(source []byte, file string, row int) *Lexer {
 	return &Lexer{
 		file:     file,
 		input:    source,
 		position: 0,
-		row:      1,
+		row:      row,
 		col:      1,
 		keywords: getKeywords(),
 		tokens:   []token.Token{},
@@ -54,7 +58,10 @@ func (l *Lexer) Tokenize() ([]token.Token, []error) {
 
 // Create new token with length and add to tokens
 func (l *Lexer) addToken(kind token.TokenType, value string, length int) {
-	l.tokens = append(l.tokens, token.NewToken(kind, value, l.row, l.col-length))
+	tok := token.NewToken(kind, value, l.row, l.col-length)
+	tok.Pos.Offset = l.position - length
+	tok.Pos.Length = length
+	l.tokens = append(l.tokens, tok)
 }
 
 func (l *Lexer) isAtEnd() bool {
@@ -185,6 +192,29 @@ func (l *Lexer) readToken() {
 	case '_':
 		l.addToken(token.UNDERSCORE, "_", 1)
 		return
+	case '@':
+		l.addToken(token.AT, "@", 1)
+		return
+	case '.':
+		if l.expect('.') {
+			if l.expect('<') {
+				l.addToken(token.DOT_DOT_LESS, "..<", 3)
+			} else {
+				l.addToken(token.DOT_DOT, "..", 2)
+			}
+		} else {
+			l.addToken(token.DOT, ".", 1)
+		}
+		return
+	case '?':
+		if l.expect('.') {
+			l.addToken(token.QUESTION_DOT, "?.", 2)
+		} else if l.expect(':') {
+			l.addToken(token.QUESTION_COLON, "?:", 2)
+		} else {
+			l.addToken(token.QUESTION, "?", 1)
+		}
+		return
 	case '+':
 		if l.expect('=') {
 			l.addToken(token.PLUS_EQUAL, "+=", 2)
@@ -231,6 +261,8 @@ func (l *Lexer) readToken() {
 	case '!':
 		if l.expect('=') {
 			l.addToken(token.BANG_EQUAL, "!=", 2)
+		} else if l.expect('!') {
+			l.addToken(token.BANG_BANG, "!!", 2)
 		} else {
 			l.addToken(token.BANG, "!", 1)
 		}
@@ -319,7 +351,11 @@ func (l *Lexer) readToken() {
 
 	if unicode.IsDigit(rune(char)) {
 		num, ttype := l.readNumber(char)
-		l.addToken(ttype, num, len(num))
+		var padding int
+		if ttype == token.BIGINT || ttype == token.DECIMAL {
+			padding = 1 // Suffix is not part of value
+		}
+		l.addToken(ttype, num, len(num)+padding)
 		return
 	}
 
@@ -334,6 +370,16 @@ func (l *Lexer) readNumber(start byte) (string, token.TokenType) {
 	ttype := token.INTEGER
 
 	for peek := rune(l.peek()); unicode.IsDigit(peek) || (peek == '.' && unicode.IsDigit(rune(l.peekNext()))) || unicode.IsLetter(peek); {
+		// Suffix 'n' marks bigint literals and 'd' decimal literals
+		if valid && (peek == 'n' && ttype == token.INTEGER || peek == 'd') && !isAlphaNumeric(rune(l.peekNext())) {
+			l.advance()
+			if peek == 'n' {
+				return sb.String(), token.BIGINT
+			}
+
+			return sb.String(), token.DECIMAL
+		}
+
 		if unicode.IsLetter(peek) {
 			valid = false
 		}
@@ -358,12 +404,17 @@ func (l *Lexer) readNumber(start byte) (string, token.TokenType) {
 	return sb.String(), ttype
 }
 
+// Check if character can continue identifier or number
+func isAlphaNumeric(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsDigit(char) || char == '_'
+}
+
 // Read identifier from input
 func (l *Lexer) readIdentifier(start byte) string {
 	var sb strings.Builder
 	sb.WriteByte(start)
 
-	for peek := rune(l.peek()); unicode.IsLetter(peek) || unicode.IsDigit(peek) || peek == '_'; {
+	for peek := rune(l.peek()); isAlphaNumeric(peek); {
 		sb.WriteByte(l.advance())
 		peek = rune(l.peek())
 	}
@@ -421,26 +472,42 @@ func (l *Lexer) readChar() (string, token.TokenType) {
 }
 
 func (l *Lexer) error(message string) {
-	err := errors.New(fmt.Sprintf("%s:%d:%d - %s", l.file, l.row, l.col, message))
-	l.errors = append(l.errors, err)
+	pos := token.Position{
+		Row:    l.row,
+		Column: l.col,
+		Offset: l.position,
+	}
+	l.errors = append(l.errors, diagnostic.NewError(l.file, diagnostic.At(pos), message))
 }
 
 // Returns map from strings to tokentype
 func getKeywords() map[string]token.TokenType {
 	return map[string]token.TokenType{
-		"false":    token.FALSE,
-		"true":     token.TRUE,
-		"if":       token.IF,
-		"else":     token.ELSE,
-		"for":      token.FOR,
-		"in":       token.IN,
-		"while":    token.WHILE,
-		"fun":      token.FUN,
-		"return":   token.RETURN,
-		"val":      token.VAL,
-		"var":      token.VAR,
-		"continue": token.CONTINUE,
-		"match":    token.MATCH,
-		"fall":     token.FALL,
+		"false":     token.FALSE,
+		"true":      token.TRUE,
+		"if":        token.IF,
+		"else":      token.ELSE,
+		"for":       token.FOR,
+		"in":        token.IN,
+		"while":     token.WHILE,
+		"fun":       token.FUN,
+		"return":    token.RETURN,
+		"val":       token.VAL,
+		"var":       token.VAR,
+		"continue":  token.CONTINUE,
+		"match":     token.MATCH,
+		"fall":      token.FALL,
+		"break":     token.BREAK,
+		"class":     token.CLASS,
+		"this":      token.THIS,
+		"enum":      token.ENUM,
+		"null":      token.NULL,
+		"interface": token.INTERFACE,
+		"is":        token.IS,
+		"as":        token.AS,
+		"try":       token.TRY,
+		"catch":     token.CATCH,
+		"finally":   token.FINALLY,
+		"throw":     token.THROW,
 	}
 }
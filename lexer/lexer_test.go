@@ -7,7 +7,7 @@ import (
 )
 
 func TestKeywords(t *testing.T) {
-	input := "if else false true for in while fun return val var continue fall match"
+	input := "if else false true for in while fun return val var continue fall match break class this enum null interface is as try catch finally throw"
 
 	lexer := NewLexer([]byte(input), "test")
 	tokens, errors := lexer.Tokenize()
@@ -88,6 +88,66 @@ func TestKeywords(t *testing.T) {
 			Value: "",
 			Pos:   token.Position{},
 		},
+		{
+			Kind:  token.BREAK,
+			Value: "",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.CLASS,
+			Value: "",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.THIS,
+			Value: "",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.ENUM,
+			Value: "",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.NULL,
+			Value: "",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.INTERFACE,
+			Value: "",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.IS,
+			Value: "is",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.AS,
+			Value: "as",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.TRY,
+			Value: "try",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.CATCH,
+			Value: "catch",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.FINALLY,
+			Value: "finally",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.THROW,
+			Value: "throw",
+			Pos:   token.Position{},
+		},
 		{
 			Kind:  token.EOF,
 			Value: "EOF",
@@ -140,6 +200,54 @@ func TestNumber(t *testing.T) {
 	verify_token_type(t, expected, tokens)
 }
 
+func TestNumberSuffix(t *testing.T) {
+	input := "123n 1.10d 5d 12nd 1.5n"
+
+	lexer := NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		for _, err := range errors {
+			t.Logf("%v", err)
+		}
+	}
+
+	expected := []token.Token{
+		{
+			Kind:  token.BIGINT,
+			Value: "123",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.DECIMAL,
+			Value: "1.10",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.DECIMAL,
+			Value: "5",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.ILLEGAL,
+			Value: "12nd",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.ILLEGAL,
+			Value: "1.5n",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.EOF,
+			Value: "EOF",
+			Pos:   token.Position{},
+		},
+	}
+
+	verify_token_type(t, expected, tokens)
+	verify_token_value(t, expected, tokens)
+}
+
 func TestStringsAndChars(t *testing.T) {
 	input := "\"Hello world\" 'c' 'a' 'invalid'"
 
@@ -184,7 +292,7 @@ func TestStringsAndChars(t *testing.T) {
 }
 
 func TestSymbols(t *testing.T) {
-	input := "(){}[],;:_"
+	input := "(){}[],;:_.@?"
 
 	lexer := NewLexer([]byte(input), "test")
 	tokens, errors := lexer.Tokenize()
@@ -245,6 +353,21 @@ func TestSymbols(t *testing.T) {
 			Value: "_",
 			Pos:   token.Position{},
 		},
+		{
+			Kind:  token.DOT,
+			Value: ".",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.AT,
+			Value: "@",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.QUESTION,
+			Value: "?",
+			Pos:   token.Position{},
+		},
 		{
 			Kind:  token.EOF,
 			Value: "EOF",
@@ -259,7 +382,7 @@ func TestSymbols(t *testing.T) {
 func TestOperators(t *testing.T) {
 	input := `+ += - -= -> / /= * *= ** **= ! 
 			  != = == > >= < <= && || & &= | |=
-			  ~ ~= ^ ^= %`
+			  ~ ~= ^ ^= % ?. ?: !!`
 
 	lexer := NewLexer([]byte(input), "test")
 	tokens, errors := lexer.Tokenize()
@@ -420,6 +543,21 @@ func TestOperators(t *testing.T) {
 			Value: "%",
 			Pos:   token.Position{},
 		},
+		{
+			Kind:  token.QUESTION_DOT,
+			Value: "?.",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.QUESTION_COLON,
+			Value: "?:",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.BANG_BANG,
+			Value: "!!",
+			Pos:   token.Position{},
+		},
 		{
 			Kind:  token.EOF,
 			Value: "EOF",
@@ -432,6 +570,69 @@ func TestOperators(t *testing.T) {
 
 }
 
+func TestRanges(t *testing.T) {
+	input := "0..10 0..<10 1.5.."
+
+	lexer := NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		for _, err := range errors {
+			t.Logf("%v", err)
+		}
+	}
+
+	expected := []token.Token{
+		{
+			Kind:  token.INTEGER,
+			Value: "0",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.DOT_DOT,
+			Value: "..",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.INTEGER,
+			Value: "10",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.INTEGER,
+			Value: "0",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.DOT_DOT_LESS,
+			Value: "..<",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.INTEGER,
+			Value: "10",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.REAL,
+			Value: "1.5",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.DOT_DOT,
+			Value: "..",
+			Pos:   token.Position{},
+		},
+		{
+			Kind:  token.EOF,
+			Value: "EOF",
+			Pos:   token.Position{},
+		},
+	}
+
+	verify_token_type(t, expected, tokens)
+	verify_token_value(t, expected, tokens)
+}
+
 func TestIdentifiers(t *testing.T) {
 	input := "variable snake_case camelCase PascalCase snake_case_with_number_1234"
 
@@ -480,6 +681,40 @@ func TestIdentifiers(t *testing.T) {
 	verify_token_value(t, expected, tokens)
 }
 
+func TestPositions(t *testing.T) {
+	input := "val s =\n  \"ab\" + 10n;"
+
+	lexer := NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		for _, err := range errors {
+			t.Logf("%v", err)
+		}
+	}
+
+	expected := []token.Position{
+		{Row: 1, Column: 1, Offset: 0, Length: 3},
+		{Row: 1, Column: 5, Offset: 4, Length: 1},
+		{Row: 1, Column: 7, Offset: 6, Length: 1},
+		{Row: 2, Column: 3, Offset: 10, Length: 4},
+		{Row: 2, Column: 8, Offset: 15, Length: 1},
+		{Row: 2, Column: 10, Offset: 17, Length: 3},
+		{Row: 2, Column: 13, Offset: 20, Length: 1},
+		{Row: 2, Column: 14, Offset: 21, Length: 0},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Errorf("Incorrect number of tokens: expected %d, got %d\n", len(expected), len(tokens))
+		return
+	}
+
+	for i := range tokens {
+		if tokens[i].Pos != expected[i] {
+			t.Errorf("Incorrect position of %s: expected %+v, got %+v\n", tokens[i].Value, expected[i], tokens[i].Pos)
+		}
+	}
+}
+
 func verify_token_type(t *testing.T, expected []token.Token, tokens []token.Token) {
 	if len(tokens) != len(expected) {
 		t.Errorf("Incorrect number of tokens: expected %d, got %d\n", len(expected), len(tokens))
@@ -1,20 +1,41 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"interpreter/ast"
+	"interpreter/diagnostic"
 	"interpreter/interpret"
 	"interpreter/lexer"
 	"interpreter/parser"
 	"interpreter/types"
 	"io"
+	"maps"
 	"os"
 
 	"github.com/chzyer/readline"
 )
 
+// Behavior of integer arithmetic on overflow
+var overflow = interpret.Wrapping
+
+// Color diagnostics when printing to a terminal
+var color = diagnostic.IsTerminal(os.Stdout)
+
+// Types of expressions checked in all runs, since functions declared on earlier lines of the repl
+// are still called by later ones
+var exprTypes = map[ast.Expr]types.Type{}
+
 func main() {
-	if len(os.Args) > 1 {
-		interpretProgram(os.Args[1])
+	checked := flag.Bool("checked", false, "report integer overflow as runtime error instead of wrapping around")
+	flag.Parse()
+
+	if *checked {
+		overflow = interpret.Checked
+	}
+
+	if flag.NArg() > 0 {
+		interpretProgram(flag.Arg(0))
 	}
 
 	repl()
@@ -32,7 +53,7 @@ func interpretProgram(path string) {
 		fmt.Fprintf(os.Stderr, err.Error())
 	}
 
-	runProgram(content, path)
+	runProgram(content, path, 1, content)
 }
 
 func repl() {
@@ -41,23 +62,27 @@ func repl() {
 		fmt.Fprintf(os.Stderr, "Failed to open repl")
 	}
 
-	for {
+	// Lines entered so far, since functions declared on earlier lines can fail later
+	var history []byte
+	for row := 1; ; row++ {
 		line, err := rl.Readline()
 		if err != nil {
 			return
 		}
 
-		runProgram([]byte(line), "repl")
+		history = append(history, line+"\n"...)
+		runProgram([]byte(line), "repl", row, history)
 	}
 
 }
 
-func runProgram(program []byte, file string) {
-	lexer := lexer.NewLexer(program, file)
+// Run program starting at row of source, which is used to show the code of errors
+func runProgram(program []byte, file string, row int, source []byte) {
+	lexer := lexer.NewLexerAt(program, file, row)
 	tokens, errors := lexer.Tokenize()
 	if errors != nil {
 		for _, err := range errors {
-			fmt.Printf("%s\n", err)
+			printError(err, source)
 		}
 	}
 
@@ -71,7 +96,7 @@ func runProgram(program []byte, file string) {
 
 	if len(errors) != 0 {
 		for _, err := range errors {
-			fmt.Printf("%s\n", err)
+			printError(err, source)
 		}
 		return
 	}
@@ -82,11 +107,32 @@ func runProgram(program []byte, file string) {
 	ok := typechecker.Visit(root)
 	if !ok {
 		for _, err := range typechecker.Errors {
-			fmt.Printf("%v\n", err)
+			printError(err, source)
 		}
 		return
 	}
 
-	interpreter := interpret.NewInterpreter()
-	interpreter.Visit(root)
+	maps.Copy(exprTypes, typechecker.Types)
+	interpreter := interpret.NewInterpreter(file, exprTypes)
+	interpreter.Overflow = overflow
+	err := interpreter.Visit(root)
+	if runtimeErr, ok := err.(*interpret.RuntimeError); ok {
+		printError(runtimeErr.Diagnostic(), source)
+
+		// Errors inside functions show the calls that led to them
+		if len(runtimeErr.Trace) > 1 {
+			fmt.Print(runtimeErr.StackTrace())
+		}
+	} else if err != nil {
+		printError(err, source)
+	}
+}
+
+// Print error, with the code it refers to if it is a diagnostic
+func printError(err error, source []byte) {
+	if d, ok := err.(*diagnostic.Diagnostic); ok {
+		fmt.Print(d.Render(source, color))
+	} else {
+		fmt.Printf("%v\n", err)
+	}
 }
@@ -1,9 +1,9 @@
 package parser
 
 import (
-	"errors"
 	"fmt"
 	"interpreter/ast"
+	"interpreter/diagnostic"
 	"interpreter/token"
 	"slices"
 )
@@ -68,16 +68,24 @@ func (p *Parser) consume(kind token.TokenType) (token.Token, error) {
 	}
 
 	tok := p.peek()
-	return token.Token{}, p.error(fmt.Sprintf("Unexpected token. Expected %v, found %v", kind, tok.Kind), tok)
+	err := p.error(fmt.Sprintf("Unexpected token. Expected %v, found %v", kind, tok.Kind), tok)
+
+	// Missing semicolons are most likely forgotten after the previous token
+	if kind == token.SEMICOLON && p.current > 0 {
+		end := p.previous().Pos.End()
+		err.WithFixIt(diagnostic.Span{Start: end, End: end}, ";")
+	}
+
+	return token.Token{}, err
 }
 
 func (p *Parser) isAtEnd() bool {
 	return p.current == len(p.tokens) || p.tokens[p.current].Kind == token.EOF
 }
 
-// Create error with message and add to list of errors
-func (p *Parser) error(message string, tok token.Token) error {
-	err := errors.New(fmt.Sprintf("%s:%d:%d - %s", p.file, tok.Pos.Row, tok.Pos.Column, message))
+// Create error with message at token and add to list of errors
+func (p *Parser) error(message string, tok token.Token) *diagnostic.Diagnostic {
+	err := diagnostic.NewError(p.file, diagnostic.At(tok.Pos), message)
 	p.errors = append(p.errors, err)
 	return err
 }
@@ -96,6 +104,15 @@ func (p *Parser) peek() token.Token {
 	return p.tokens[p.current]
 }
 
+// Peek two tokens ahead without advancing
+// Returns last token if at end
+func (p *Parser) peekNext() token.Token {
+	if p.isAtEnd() {
+		return p.peek()
+	}
+	return p.tokens[p.current+1]
+}
+
 // Return previous token
 func (p *Parser) previous() token.Token {
 	return p.tokens[p.current-1]
@@ -110,7 +127,7 @@ func (p *Parser) synchronize() {
 			return
 		}
 
-		stmt_start := []token.TokenType{token.FOR, token.FUN, token.IF, token.RETURN, token.VAR, token.VAL, token.WHILE}
+		stmt_start := []token.TokenType{token.BREAK, token.CLASS, token.CONTINUE, token.ENUM, token.FOR, token.FUN, token.IF, token.INTERFACE, token.RETURN, token.THROW, token.TRY, token.VAR, token.VAL, token.WHILE}
 		if slices.Contains(stmt_start, p.peek().Kind) {
 			return
 		}
@@ -125,6 +142,11 @@ func (p *Parser) statement() (ast.Stmt, error) {
 		return p.variableDeclaration()
 	}
 
+	// Braces followed by lambda parameters are lambdas, e.g. a lambda returned from a lambda
+	if p.check(token.LEFT_BRACE) && p.lambdaAhead() {
+		return p.expressionStatement()
+	}
+
 	if p.expect([]token.TokenType{token.LEFT_BRACE}) {
 		left_brace := p.previous()
 
@@ -143,17 +165,774 @@ func (p *Parser) statement() (ast.Stmt, error) {
 		return p.ifStmt()
 	}
 
-	if p.expect([]token.TokenType{token.WHILE}) {
-		return p.whileStmt()
+	if p.expect([]token.TokenType{token.WHILE}) {
+		return p.whileStmt("")
+	}
+
+	if p.expect([]token.TokenType{token.FOR}) {
+		return p.forStmt("")
+	}
+
+	// Labeled loop
+	if p.check(token.IDENT) && p.peekNext().Kind == token.AT {
+		return p.labeledLoop()
+	}
+
+	if p.expect([]token.TokenType{token.BREAK, token.CONTINUE}) {
+		return p.jumpStmt()
+	}
+
+	if p.expect([]token.TokenType{token.FUN}) {
+		return p.funDeclaration()
+	}
+
+	if p.expect([]token.TokenType{token.RETURN}) {
+		return p.returnStmt()
+	}
+
+	if p.expect([]token.TokenType{token.THROW}) {
+		return p.throwStmt()
+	}
+
+	if p.expect([]token.TokenType{token.CLASS}) {
+		return p.classDeclaration(false)
+	}
+
+	if p.expect([]token.TokenType{token.ENUM}) {
+		return p.enumDeclaration()
+	}
+
+	if p.expect([]token.TokenType{token.INTERFACE}) {
+		return p.interfaceDeclaration()
+	}
+
+	// 'data' is only a keyword in front of 'class'
+	if p.check(token.IDENT) && p.peek().Value == "data" && p.peekNext().Kind == token.CLASS {
+		p.advance()
+		p.advance()
+		return p.classDeclaration(true)
+	}
+
+	return p.expressionStatement()
+}
+
+// Parse class declaration
+//
+//	class ::= "data"? "class" IDENTIFIER typeParams? "(" ( field ( "," field )* )? ")" ( ":" IDENTIFIER ( "," IDENTIFIER )* )? ( "{" ( "fun" function )* "}" )?;
+//	field ::= ( "val" | "var" ) IDENTIFIER ":" type;
+func (p *Parser) classDeclaration(data bool) (ast.Stmt, error) {
+	class := p.previous()
+
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	type_params, err := p.typeParams()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.LEFT_PAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []*ast.Field{}
+	if !p.check(token.RIGHT_PAREN) {
+		for {
+			field, err := p.field()
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, field)
+
+			if !p.expect([]token.TokenType{token.COMMA}) {
+				break
+			}
+		}
+	}
+
+	_, err = p.consume(token.RIGHT_PAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Implemented interfaces
+	interfaces := []*ast.NamedType{}
+	if p.expect([]token.TokenType{token.COLON}) {
+		for {
+			name, err := p.consume(token.IDENT)
+			if err != nil {
+				return nil, err
+			}
+
+			interfaces = append(interfaces, &ast.NamedType{
+				Pos:  name.Pos,
+				Name: name.Value,
+			})
+
+			if !p.expect([]token.TokenType{token.COMMA}) {
+				break
+			}
+		}
+	}
+
+	// Body with methods is optional
+	methods := []*ast.FunDeclaration{}
+	if p.expect([]token.TokenType{token.LEFT_BRACE}) {
+		for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+			_, err := p.consume(token.FUN)
+			if err != nil {
+				return nil, err
+			}
+
+			method, err := p.funDeclaration()
+			if err != nil {
+				return nil, err
+			}
+
+			methods = append(methods, method.(*ast.FunDeclaration))
+		}
+
+		_, err = p.consume(token.RIGHT_BRACE)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		p.expect([]token.TokenType{token.SEMICOLON})
+	}
+
+	return &ast.ClassDeclaration{
+		Pos:        class.Pos,
+		Data:       data,
+		Name:       name.Value,
+		TypeParams: type_params,
+		Fields:     fields,
+		Interfaces: interfaces,
+		Methods:    methods,
+	}, nil
+}
+
+// Parse interface declaration
+//
+//	interface ::= "interface" IDENTIFIER "{" ( "fun" signature ";"? )* "}";
+func (p *Parser) interfaceDeclaration() (ast.Stmt, error) {
+	keyword := p.previous()
+
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.LEFT_BRACE)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := []*ast.FunDeclaration{}
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		_, err := p.consume(token.FUN)
+		if err != nil {
+			return nil, err
+		}
+
+		method, err := p.signature()
+		if err != nil {
+			return nil, err
+		}
+
+		methods = append(methods, method)
+		p.expect([]token.TokenType{token.SEMICOLON})
+	}
+
+	_, err = p.consume(token.RIGHT_BRACE)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.InterfaceDeclaration{
+		Pos:     keyword.Pos,
+		Name:    name.Value,
+		Methods: methods,
+	}, nil
+}
+
+// Parse enum declaration
+//
+//	enum ::= "enum" IDENTIFIER "{" variant ( "," variant )* ","? "}";
+//	variant ::= IDENTIFIER ( "(" ( parameter ( "," parameter )* )? ")" )?;
+func (p *Parser) enumDeclaration() (ast.Stmt, error) {
+	enum := p.previous()
+
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.LEFT_BRACE)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := []*ast.Variant{}
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		variant, err := p.variant()
+		if err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, variant)
+
+		if !p.expect([]token.TokenType{token.COMMA}) {
+			break
+		}
+	}
+
+	_, err = p.consume(token.RIGHT_BRACE)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.EnumDeclaration{
+		Pos:      enum.Pos,
+		Name:     name.Value,
+		Variants: variants,
+	}, nil
+}
+
+// Parse variant of enum
+func (p *Parser) variant() (*ast.Variant, error) {
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []*ast.Parameter{}
+	if p.expect([]token.TokenType{token.LEFT_PAREN}) {
+		if !p.check(token.RIGHT_PAREN) {
+			for {
+				field, err := p.parameter()
+				if err != nil {
+					return nil, err
+				}
+
+				fields = append(fields, field)
+
+				if !p.expect([]token.TokenType{token.COMMA}) {
+					break
+				}
+			}
+		}
+
+		_, err = p.consume(token.RIGHT_PAREN)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ast.Variant{
+		Pos:    name.Pos,
+		Name:   name.Value,
+		Fields: fields,
+	}, nil
+}
+
+// Parse field declared in primary constructor
+func (p *Parser) field() (*ast.Field, error) {
+	if !p.expect([]token.TokenType{token.VAL, token.VAR}) {
+		return nil, p.error("Expected 'val' or 'var' before field", p.peek())
+	}
+	decl_type := p.previous()
+
+	param, err := p.parameter()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Field{
+		Pos:      param.Pos,
+		Name:     param.Name,
+		DeclType: decl_type.Kind,
+		Type:     param.Type,
+	}, nil
+}
+
+// Parse function declaration
+//
+//	function ::= signature block;
+func (p *Parser) funDeclaration() (ast.Stmt, error) {
+	decl, err := p.signature()
+	if err != nil {
+		return nil, err
+	}
+
+	lbrace, err := p.consume(token.LEFT_BRACE)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	decl.Body = &ast.BlockStmt{
+		Pos:   lbrace.Pos,
+		Stmts: statements,
+	}
+
+	return decl, nil
+}
+
+// Parse signature of function, used for declarations and interface methods
+// 'fun' is already consumed
+//
+//	signature ::= typeParams? IDENTIFIER "(" ( parameter ( "," parameter )* )? ")" ( ":" type )?;
+func (p *Parser) signature() (*ast.FunDeclaration, error) {
+	fun := p.previous()
+
+	type_params, err := p.typeParams()
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.LEFT_PAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	params := []*ast.Parameter{}
+	if !p.check(token.RIGHT_PAREN) {
+		for {
+			param, err := p.parameter()
+			if err != nil {
+				return nil, err
+			}
+
+			params = append(params, param)
+
+			if !p.expect([]token.TokenType{token.COMMA}) {
+				break
+			}
+		}
+	}
+
+	_, err = p.consume(token.RIGHT_PAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return type is optional, functions without one return unit
+	var return_type ast.TypeExpr
+	if p.expect([]token.TokenType{token.COLON}) {
+		return_type, err = p.typeExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ast.FunDeclaration{
+		Pos:        fun.Pos,
+		Name:       name.Value,
+		TypeParams: type_params,
+		Params:     params,
+		ReturnType: return_type,
+	}, nil
+}
+
+// Parse type parameters of generic function or class
+// Returns nil if there are none
+//
+//	typeParams ::= "<" IDENTIFIER ( "," IDENTIFIER )* ">";
+func (p *Parser) typeParams() ([]*ast.TypeParam, error) {
+	if !p.expect([]token.TokenType{token.LESS}) {
+		return nil, nil
+	}
+
+	params := []*ast.TypeParam{}
+	for {
+		name, err := p.consume(token.IDENT)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, &ast.TypeParam{
+			Pos:  name.Pos,
+			Name: name.Value,
+		})
+
+		if !p.expect([]token.TokenType{token.COMMA}) {
+			break
+		}
+	}
+
+	_, err := p.consume(token.GREATER)
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// Parse single function parameter
+func (p *Parser) parameter() (*ast.Parameter, error) {
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.COLON)
+	if err != nil {
+		return nil, err
+	}
+
+	param_type, err := p.typeExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Parameter{
+		Pos:  name.Pos,
+		Name: name.Value,
+		Type: param_type,
+	}, nil
+}
+
+// Parse lambda parameter
+// Type is inferred if omitted
+//
+//	lambdaParameter ::= IDENT ( ":" type )?;
+func (p *Parser) lambdaParameter() (*ast.Parameter, error) {
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	var param_type ast.TypeExpr
+	if p.expect([]token.TokenType{token.COLON}) {
+		param_type, err = p.typeExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ast.Parameter{
+		Pos:  name.Pos,
+		Name: name.Value,
+		Type: param_type,
+	}, nil
+}
+
+// Parse type with optional '?' marking it as nullable
+//
+//	type ::= baseType "?"?;
+func (p *Parser) typeExpr() (ast.TypeExpr, error) {
+	base, err := p.baseType()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.expect([]token.TokenType{token.QUESTION}) {
+		return &ast.NullableType{
+			Pos:  base.Position(),
+			Elem: base,
+		}, nil
+	}
+
+	return base, nil
+}
+
+// Parse type without nullability
+//
+//	baseType ::= IDENTIFIER ( "<" type ( "," type )* ">" )? | "[" type ( ":" type )? "]" | "(" ( type ( "," type )* )? ")" "->" type | "(" type ")";
+func (p *Parser) baseType() (ast.TypeExpr, error) {
+	if p.expect([]token.TokenType{token.LEFT_BRACKET}) {
+		lbracket := p.previous()
+
+		elem, err := p.typeExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		// Map type, e.g. [string: int]
+		if p.expect([]token.TokenType{token.COLON}) {
+			value, err := p.typeExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = p.consume(token.RIGHT_BRACKET)
+			if err != nil {
+				return nil, err
+			}
+
+			return &ast.MapType{
+				Pos:   lbracket.Pos,
+				Key:   elem,
+				Value: value,
+			}, nil
+		}
+
+		_, err = p.consume(token.RIGHT_BRACKET)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.ListType{
+			Pos:  lbracket.Pos,
+			Elem: elem,
+		}, nil
+	}
+
+	if p.expect([]token.TokenType{token.LEFT_PAREN}) {
+		lparen := p.previous()
+
+		params := []ast.TypeExpr{}
+		if !p.check(token.RIGHT_PAREN) {
+			for {
+				param, err := p.typeExpr()
+				if err != nil {
+					return nil, err
+				}
+
+				params = append(params, param)
+
+				if !p.expect([]token.TokenType{token.COMMA}) {
+					break
+				}
+			}
+		}
+
+		_, err := p.consume(token.RIGHT_PAREN)
+		if err != nil {
+			return nil, err
+		}
+
+		// Parenthesized type, e.g. (() -> int)?
+		if len(params) == 1 && !p.check(token.MINUS_GREATER) {
+			return params[0], nil
+		}
+
+		_, err = p.consume(token.MINUS_GREATER)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := p.typeExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.FunctionType{
+			Pos:    lparen.Pos,
+			Params: params,
+			Return: ret,
+		}, nil
+	}
+
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	// Type arguments
+	var args []ast.TypeExpr
+	if p.expect([]token.TokenType{token.LESS}) {
+		for {
+			arg, err := p.typeExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, arg)
+
+			if !p.expect([]token.TokenType{token.COMMA}) {
+				break
+			}
+		}
+
+		_, err = p.consume(token.GREATER)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ast.NamedType{
+		Pos:  name.Pos,
+		Name: name.Value,
+		Args: args,
+	}, nil
+}
+
+// Parse return statement
+func (p *Parser) returnStmt() (ast.Stmt, error) {
+	ret := p.previous()
+
+	var value ast.Expr
+	if !p.check(token.SEMICOLON) {
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		value = v
+	}
+
+	_, err := p.consume(token.SEMICOLON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ReturnStmt{
+		Pos:   ret.Pos,
+		Value: value,
+	}, nil
+}
+
+// Parse throw statement
+//
+//	throw ::= "throw" expression ";";
+func (p *Parser) throwStmt() (ast.Stmt, error) {
+	throw := p.previous()
+
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.SEMICOLON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ThrowStmt{
+		Pos:   throw.Pos,
+		Value: value,
+	}, nil
+}
+
+// Parse while loop
+func (p *Parser) whileStmt(label string) (ast.Stmt, error) {
+	while := p.previous()
+	condition, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	lbrace, err := p.consume(token.LEFT_BRACE)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	block := &ast.BlockStmt{
+		Pos:   lbrace.Pos,
+		Stmts: body,
+	}
+
+	return &ast.WhileStmt{
+		Pos:       while.Pos,
+		Label:     label,
+		Condition: condition,
+		Block:     block,
+	}, nil
+}
+
+// Parse loop with label
+//
+//	labeledLoop ::= IDENTIFIER "@" ( whileStmt | forStmt );
+func (p *Parser) labeledLoop() (ast.Stmt, error) {
+	label := p.advance()
+	p.advance()
+
+	if p.expect([]token.TokenType{token.WHILE}) {
+		return p.whileStmt(label.Value)
+	}
+
+	if p.expect([]token.TokenType{token.FOR}) {
+		return p.forStmt(label.Value)
+	}
+
+	return nil, p.error("Expected loop after label", p.peek())
+}
+
+// Parse break and continue statements with optional label
+func (p *Parser) jumpStmt() (ast.Stmt, error) {
+	keyword := p.previous()
+
+	label := ""
+	if p.expect([]token.TokenType{token.AT}) {
+		name, err := p.consume(token.IDENT)
+		if err != nil {
+			return nil, err
+		}
+		label = name.Value
+	}
+
+	_, err := p.consume(token.SEMICOLON)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyword.Kind == token.BREAK {
+		return &ast.BreakStmt{
+			Pos:   keyword.Pos,
+			Label: label,
+		}, nil
+	}
+
+	return &ast.ContinueStmt{
+		Pos:   keyword.Pos,
+		Label: label,
+	}, nil
+}
+
+// Parse for loop
+func (p *Parser) forStmt(label string) (ast.Stmt, error) {
+	for_token := p.previous()
+
+	// Map entries are bound to a pair of names, e.g. 'for (k, v) in m'
+	var value token.Token
+	destructure := p.expect([]token.TokenType{token.LEFT_PAREN})
+
+	name, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	if destructure {
+		_, err = p.consume(token.COMMA)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err = p.consume(token.IDENT)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(token.RIGHT_PAREN)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return p.expressionStatement()
-}
+	_, err = p.consume(token.IN)
+	if err != nil {
+		return nil, err
+	}
 
-// Parse while loop
-func (p *Parser) whileStmt() (ast.Stmt, error) {
-	while := p.previous()
-	condition, err := p.expression()
+	iterable, err := p.expression()
 	if err != nil {
 		return nil, err
 	}
@@ -164,16 +943,20 @@ func (p *Parser) whileStmt() (ast.Stmt, error) {
 	}
 
 	body, err := p.block()
-
-	block := &ast.BlockStmt{
-		Pos:   lbrace.Pos,
-		Stmts: body,
+	if err != nil {
+		return nil, err
 	}
 
-	return &ast.WhileStmt{
-		Pos:       while.Pos,
-		Condition: condition,
-		Block:     block,
+	return &ast.ForStmt{
+		Pos:      for_token.Pos,
+		Label:    label,
+		Name:     name.Value,
+		Value:    value.Value,
+		Iterable: iterable,
+		Block: &ast.BlockStmt{
+			Pos:   lbrace.Pos,
+			Stmts: body,
+		},
 	}, nil
 }
 
@@ -241,12 +1024,11 @@ func (p *Parser) variableDeclaration() (ast.Stmt, error) {
 		return nil, err
 	}
 
-	var var_type *token.Token
+	var var_type ast.TypeExpr
 	var_type = nil
 
 	if p.expect([]token.TokenType{token.COLON}) {
-		t, err := p.consume(token.IDENT)
-		var_type = &t
+		var_type, err = p.typeExpr()
 		if err != nil {
 			return nil, err
 		}
@@ -311,105 +1093,477 @@ func (p *Parser) expressionStatement() (ast.Stmt, error) {
 	}
 
 	// Parse assignment
-	if p.check(token.EQUAL) {
-		equals := p.advance()
+	assignment_ops := []token.TokenType{
+		token.EQUAL, token.PLUS_EQUAL, token.MINUS_EQUAL, token.STAR_EQUAL, token.SLASH_EQUAL,
+		token.STAR_STAR_EQUAL, token.AND_EQUAL, token.OR_EQUAL, token.CARET_EQUAL, token.TILDE_EQUAL,
+	}
+	if p.expect(assignment_ops) {
+		equals := p.previous()
 		value, err := p.expression()
 		if err != nil {
 			return nil, err
 		}
 
-		if ident, ok := expr.(*ast.Ident); ok {
+		switch target := expr.(type) {
+		case *ast.Ident:
 			_, err = p.consume(token.SEMICOLON)
 			if err != nil {
 				return nil, err
 			}
 
 			assignment := &ast.AssignmentStmt{
-				Pos:   ident.Position(),
-				Name:  ident.Name,
+				Pos:   target.Position(),
+				Name:  target.Name,
+				Op:    equals,
 				Value: value,
 			}
 
 			return assignment, nil
+		case *ast.GetExpr:
+			_, err = p.consume(token.SEMICOLON)
+			if err != nil {
+				return nil, err
+			}
+
+			assignment := &ast.FieldAssignmentStmt{
+				Pos:    target.Position(),
+				Object: target.Object,
+				Name:   target.Name,
+				Op:     equals,
+				Value:  value,
+			}
+
+			return assignment, nil
+		case *ast.IndexExpr:
+			_, err = p.consume(token.SEMICOLON)
+			if err != nil {
+				return nil, err
+			}
+
+			assignment := &ast.IndexAssignmentStmt{
+				Pos:    target.Position(),
+				Object: target.Object,
+				Index:  target.Index,
+				Op:     equals,
+				Value:  value,
+			}
+
+			return assignment, nil
+		}
+
+		err = p.error("Invalid assignment target", equals)
+		return nil, err
+	}
+
+	// Allow if and match expressions without semicolon at end,
+	// and omitting the semicolon after the last expression in a block
+	if !endsWithBlock(expr) && !p.check(token.RIGHT_BRACE) {
+		_, err = p.consume(token.SEMICOLON)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stmt := &ast.ExprStmt{
+		Pos:  expr.Position(),
+		Expr: expr,
+	}
+
+	return stmt, nil
+}
+
+// Check if expression ends with a block and can be used
+// as a statement without a trailing semicolon
+func endsWithBlock(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.IfExpr, *ast.MatchExpr, *ast.TryExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+Precedence:
+
+	expression ::= lor;
+	lor := land ("or" land)*;
+	land := equality ("and" equality)*;
+	equality ::= comparison ( ( "!=" | "==") comparison)*;
+	comparison ::= range ( ( ">" | ">=" | "<=" | "<") range)*;
+	range ::= term ( ( ".." | "..<" ) term ( "step" term )? )?;
+	term ::= factor ( ( "-" | "+" ) factor)*;
+	factor ::= cast ( ( "/" | "*" | "%") cast)*;
+	cast ::= unary ( "as" "?"? type )*;
+	unary ::= ("!" | "-") unary | exponent;
+	exponent ::= call ("**") call | call;
+	call ::= primary ( "(" arguments? ")" | "[" expression "]" | ( "." | "?." ) IDENTIFIER | "!!" | "?" )*;
+	arguments ::= argument ( "," argument )*;
+	argument ::= ( IDENTIFIER "=" )? expression;
+	primary ::=  IDENTIFIER | INTEGER | REAL | BIGINT | DECIMAL | STRING | CHAR | "true" | "false" | "this" | "(" expression ")" | list | map | lambda;
+	list ::= "[" ( expression ( "," expression )* )? "]";
+	map ::= "[" ( ":" | expression ":" expression ( "," expression ":" expression )* ) "]";
+*/
+
+// Parse expression
+func (p *Parser) expression() (ast.Expr, error) {
+	if p.check(token.IF) {
+		p.advance()
+		return p.ifExpr()
+	}
+
+	if p.check(token.MATCH) {
+		p.advance()
+		return p.matchExpr()
+	}
+
+	if p.check(token.TRY) {
+		p.advance()
+		return p.tryExpr()
+	}
+
+	return p.logicalOr()
+}
+
+// Parse match expressions
+//
+//	match ::= "match" expression "{" ( arm ( "," arm )* ","? )? "}";
+//	arm ::= pattern ( "if" expression )? "->" ( "fall" | blockExpr | expression );
+func (p *Parser) matchExpr() (ast.Expr, error) {
+	match := p.previous()
+
+	subject, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.LEFT_BRACE)
+	if err != nil {
+		return nil, err
+	}
+
+	arms := []*ast.MatchArm{}
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		arm, err := p.matchArm()
+		if err != nil {
+			return nil, err
+		}
+
+		arms = append(arms, arm)
+
+		// Comma is optional after block bodies and the last arm
+		if _, ok := arm.Body.(*ast.BlockExpr); ok || p.check(token.RIGHT_BRACE) {
+			p.expect([]token.TokenType{token.COMMA})
+			continue
+		}
+
+		_, err = p.consume(token.COMMA)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.consume(token.RIGHT_BRACE)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.MatchExpr{
+		Pos:     match.Pos,
+		Subject: subject,
+		Arms:    arms,
+	}, nil
+}
+
+// Parse single arm of match expression
+func (p *Parser) matchArm() (*ast.MatchArm, error) {
+	pattern, err := p.pattern()
+	if err != nil {
+		return nil, err
+	}
+
+	var guard ast.Expr
+	if p.expect([]token.TokenType{token.IF}) {
+		guard, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.consume(token.MINUS_GREATER)
+	if err != nil {
+		return nil, err
+	}
+
+	arm := &ast.MatchArm{
+		Pos:     pattern.Position(),
+		Pattern: pattern,
+		Guard:   guard,
+	}
+
+	if p.expect([]token.TokenType{token.FALL}) {
+		arm.Fall = true
+		return arm, nil
+	}
+
+	if p.check(token.LEFT_BRACE) {
+		arm.Body, err = p.blockExpr()
+	} else {
+		arm.Body, err = p.expression()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return arm, nil
+}
+
+// Parse pattern in match arm
+//
+//	pattern ::= "_" | IDENTIFIER | variantPattern | literal ( ( ".." | "..<" ) literal )?;
+func (p *Parser) pattern() (ast.Pattern, error) {
+	if p.expect([]token.TokenType{token.UNDERSCORE}) {
+		return &ast.WildcardPattern{Pos: p.previous().Pos}, nil
+	}
+
+	// Variant of enum, e.g. Shape.Circle(r), or of result, e.g. Ok(v)
+	if p.check(token.IDENT) && (p.peekNext().Kind == token.DOT || p.peekNext().Kind == token.LEFT_PAREN) {
+		return p.variantPattern()
+	}
+
+	if p.check(token.IDENT) {
+		ident := p.advance()
+		return &ast.BindingPattern{
+			Pos:  ident.Pos,
+			Name: ident.Value,
+		}, nil
+	}
+
+	start, err := p.patternLiteral()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.expect([]token.TokenType{token.DOT_DOT, token.DOT_DOT_LESS}) {
+		op := p.previous()
+		end, err := p.patternLiteral()
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.RangePattern{
+			Pos:       start.Pos,
+			Start:     start,
+			End:       end,
+			Inclusive: op.Kind == token.DOT_DOT,
+		}, nil
+	}
+
+	return &ast.LiteralPattern{
+		Pos:   start.Pos,
+		Value: start,
+	}, nil
+}
+
+// Parse pattern matching variant of enum
+// Variants of results are written without enum name
+//
+//	variantPattern ::= ( IDENTIFIER "." )? IDENTIFIER ( "(" pattern ( "," pattern )* ")" )?;
+func (p *Parser) variantPattern() (ast.Pattern, error) {
+	start := p.peek()
+
+	enum := ""
+	if p.peekNext().Kind == token.DOT {
+		enum = p.advance().Value
+		p.advance()
+	}
+
+	variant, err := p.consume(token.IDENT)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []ast.Pattern{}
+	if p.expect([]token.TokenType{token.LEFT_PAREN}) {
+		for {
+			field, err := p.pattern()
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, field)
+
+			if !p.expect([]token.TokenType{token.COMMA}) {
+				break
+			}
+		}
+
+		_, err = p.consume(token.RIGHT_PAREN)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ast.VariantPattern{
+		Pos:     start.Pos,
+		Enum:    enum,
+		Variant: variant.Value,
+		Fields:  fields,
+	}, nil
+}
+
+// Parse literal in pattern
+// Numeric literals can be negated
+func (p *Parser) patternLiteral() (*ast.LiteralExpr, error) {
+	var minus *token.Token
+	if p.expect([]token.TokenType{token.MINUS}) {
+		op := p.previous()
+		minus = &op
+
+		if !token.IsNumber(p.peek().Kind) {
+			return nil, p.error("Expected number after '-' in pattern", p.peek())
+		}
+	}
+
+	literals := []token.TokenType{token.INTEGER, token.REAL, token.BIGINT, token.DECIMAL, token.STRING, token.CHAR, token.TRUE, token.FALSE}
+	if !p.expect(literals) {
+		return nil, p.error("Expected pattern", p.peek())
+	}
+
+	literal := p.previous()
+	if minus != nil {
+		return &ast.LiteralExpr{
+			Pos:   minus.Pos,
+			Kind:  literal.Kind,
+			Value: "-" + literal.Value,
+		}, nil
+	}
+
+	return &ast.LiteralExpr{
+		Pos:   literal.Pos,
+		Kind:  literal.Kind,
+		Value: literal.Value,
+	}, nil
+}
+
+// Parse if expressions
+func (p *Parser) ifExpr() (ast.Expr, error) {
+	if_token := p.previous()
+	condition, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	then, err := p.blockExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	// Else branch is mandatory in if expression
+	// Optional in if statement
+	_, err = p.consume(token.ELSE)
+	if err != nil {
+		return nil, err
+	}
+
+	otherwise, err := p.blockExpr()
+	if err != nil {
+		return nil, err
+	}
+	expr := &ast.IfExpr{
+		Pos:       if_token.Pos,
+		Condition: condition,
+		Then:      then,
+		Else:      otherwise,
+	}
+
+	return expr, nil
+}
+
+// Parse try expressions
+//
+//	try ::= "try" blockExpr catch* ( "finally" blockExpr )?;
+//	catch ::= "catch" "(" IDENTIFIER ":" type ")" blockExpr;
+func (p *Parser) tryExpr() (ast.Expr, error) {
+	try := p.previous()
+
+	body, err := p.blockExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	catches := []*ast.CatchClause{}
+	for p.expect([]token.TokenType{token.CATCH}) {
+		catch, err := p.catchClause()
+		if err != nil {
+			return nil, err
 		}
 
-		err = p.error("Invalid assignment target", equals)
-		return nil, err
+		catches = append(catches, catch)
 	}
 
-	// Allow if expressions without semicolon at end
-	if _, ok := expr.(*ast.IfExpr); !ok {
-		_, err = p.consume(token.SEMICOLON)
+	var finally *ast.BlockExpr
+	if p.expect([]token.TokenType{token.FINALLY}) {
+		finally, err = p.blockExpr()
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	stmt := &ast.ExprStmt{
-		Pos:  expr.Position(),
-		Expr: expr,
+	if len(catches) == 0 && finally == nil {
+		return nil, p.error("Expected 'catch' or 'finally' after try block", p.peek())
 	}
 
-	return stmt, nil
+	return &ast.TryExpr{
+		Pos:     try.Pos,
+		Body:    body,
+		Catches: catches,
+		Finally: finally,
+	}, nil
 }
 
-/*
-Precedence:
-
-	expression ::= lor;
-	lor := land ("or" land)*;
-	land := equality ("and" equality)*;
-	equality ::= comparison ( ( "!=" | "==") comparison)*;
-	comparison ::= term ( ( ">" | ">=" | "<=" | "<") term)*;
-	term ::= factor ( ( "-" | "+" ) factor)*;
-	factor ::= unary ( ( "/" | "*" | "%") unary)*;
-	unary ::= ("!" | "-") unary | exponent;
-	exponent ::= primary ("**") primary | primary;
-	primary ::=  IDENTIFIER | INTEGER | REAL | STRING | "true" | "false" | "(" expression ")";
-*/
+// Parse handler of try expression
+// 'catch' is already consumed
+func (p *Parser) catchClause() (*ast.CatchClause, error) {
+	catch := p.previous()
 
-// Parse expression
-func (p *Parser) expression() (ast.Expr, error) {
-	if p.check(token.IF) {
-		p.advance()
-		return p.ifExpr()
+	_, err := p.consume(token.LEFT_PAREN)
+	if err != nil {
+		return nil, err
 	}
 
-	return p.logicalOr()
-}
-
-// Parse if expressions
-func (p *Parser) ifExpr() (ast.Expr, error) {
-	if_token := p.previous()
-	condition, err := p.expression()
+	name, err := p.consume(token.IDENT)
 	if err != nil {
 		return nil, err
 	}
 
-	then, err := p.blockExpr()
+	_, err = p.consume(token.COLON)
 	if err != nil {
 		return nil, err
 	}
 
-	// Else branch is mandatory in if expression
-	// Optional in if statement
-	_, err = p.consume(token.ELSE)
+	t, err := p.typeExpr()
 	if err != nil {
 		return nil, err
 	}
 
-	otherwise, err := p.blockExpr()
+	_, err = p.consume(token.RIGHT_PAREN)
 	if err != nil {
 		return nil, err
 	}
-	expr := &ast.IfExpr{
-		Pos:       if_token.Pos,
-		Condition: condition,
-		Then:      then,
-		Else:      otherwise,
+
+	body, err := p.blockExpr()
+	if err != nil {
+		return nil, err
 	}
 
-	return expr, nil
+	return &ast.CatchClause{
+		Pos:  catch.Pos,
+		Name: name.Value,
+		Type: t,
+		Body: body,
+	}, nil
 }
 
 // Parse expression blocks
@@ -524,14 +1678,14 @@ func (p *Parser) equality() (ast.Expr, error) {
 
 // Parse expressions with same precedence as comparisons
 func (p *Parser) comparison() (ast.Expr, error) {
-	term, err := p.term()
+	term, err := p.typeCheck()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.expect([]token.TokenType{token.GREATER, token.GREATER_EQUAL, token.LESS_EQUAL, token.LESS}) {
 		op := p.previous()
-		right, err := p.term()
+		right, err := p.typeCheck()
 		if err != nil {
 			return nil, err
 		}
@@ -547,6 +1701,94 @@ func (p *Parser) comparison() (ast.Expr, error) {
 	return term, nil
 }
 
+// Parse check of runtime type
+//
+//	typeCheck ::= elvis ( "is" type )?;
+func (p *Parser) typeCheck() (ast.Expr, error) {
+	expr, err := p.elvis()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.expect([]token.TokenType{token.IS}) {
+		return expr, nil
+	}
+
+	is := p.previous()
+	t, err := p.typeExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.IsExpr{
+		Expr: expr,
+		Pos:  is.Pos,
+		Type: t,
+	}, nil
+}
+
+// Parse elvis operator (right associative)
+//
+//	elvis ::= range ( "?:" elvis )?;
+func (p *Parser) elvis() (ast.Expr, error) {
+	left, err := p.rangeExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.expect([]token.TokenType{token.QUESTION_COLON}) {
+		return left, nil
+	}
+
+	op := p.previous()
+	right, err := p.elvis()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ElvisExpr{
+		Left:  left,
+		Pos:   op.Pos,
+		Right: right,
+	}, nil
+}
+
+// Parse ranges with optional step
+// 'step' is only treated as a keyword after a range
+func (p *Parser) rangeExpr() (ast.Expr, error) {
+	start, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.expect([]token.TokenType{token.DOT_DOT, token.DOT_DOT_LESS}) {
+		return start, nil
+	}
+
+	op := p.previous()
+	end, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	var step ast.Expr
+	if p.check(token.IDENT) && p.peek().Value == "step" {
+		p.advance()
+		step, err = p.term()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ast.RangeExpr{
+		Start:     start,
+		Pos:       op.Pos,
+		End:       end,
+		Inclusive: op.Kind == token.DOT_DOT,
+		Step:      step,
+	}, nil
+}
+
 // Parse binary PLUS and MINUS
 func (p *Parser) term() (ast.Expr, error) {
 	factor, err := p.factor()
@@ -574,14 +1816,14 @@ func (p *Parser) term() (ast.Expr, error) {
 
 // Parse binary division, multiplication and modulo
 func (p *Parser) factor() (ast.Expr, error) {
-	unary, err := p.unary()
+	unary, err := p.cast()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.expect([]token.TokenType{token.SLASH, token.STAR, token.PERCENT}) {
 		op := p.previous()
-		right, err := p.unary()
+		right, err := p.cast()
 		if err != nil {
 			return nil, err
 		}
@@ -597,6 +1839,35 @@ func (p *Parser) factor() (ast.Expr, error) {
 	return unary, nil
 }
 
+// Parse casts to other types
+//
+//	cast ::= unary ( "as" "?"? type )*;
+func (p *Parser) cast() (ast.Expr, error) {
+	expr, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.expect([]token.TokenType{token.AS}) {
+		as := p.previous()
+		safe := p.expect([]token.TokenType{token.QUESTION})
+
+		t, err := p.typeExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.CastExpr{
+			Expr: expr,
+			Pos:  as.Pos,
+			Type: t,
+			Safe: safe,
+		}
+	}
+
+	return expr, nil
+}
+
 // Parse unary expressions
 func (p *Parser) unary() (ast.Expr, error) {
 	if p.expect([]token.TokenType{token.BANG, token.MINUS}) {
@@ -613,19 +1884,41 @@ func (p *Parser) unary() (ast.Expr, error) {
 		}, nil
 	}
 
+	// '!!' in prefix position is a double negation
+	if p.expect([]token.TokenType{token.BANG_BANG}) {
+		bang := p.previous()
+		unary, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		op := token.NewToken(token.BANG, "!", bang.Pos.Row, bang.Pos.Column)
+		inner := &ast.UnaryExpr{
+			Pos:  token.Position{Row: bang.Pos.Row, Column: bang.Pos.Column + 1},
+			Op:   token.NewToken(token.BANG, "!", bang.Pos.Row, bang.Pos.Column+1),
+			Expr: unary,
+		}
+
+		return &ast.UnaryExpr{
+			Pos:  op.Pos,
+			Op:   op,
+			Expr: inner,
+		}, nil
+	}
+
 	return p.exponent()
 }
 
 // Parse exponent expressions
 func (p *Parser) exponent() (ast.Expr, error) {
-	primary, err := p.primary()
+	primary, err := p.call()
 	if err != nil {
 		return nil, err
 	}
 
 	if p.expect([]token.TokenType{token.STAR_STAR}) {
 		op := p.previous()
-		right, err := p.primary()
+		right, err := p.call()
 		if err != nil {
 			return nil, err
 		}
@@ -641,6 +1934,103 @@ func (p *Parser) exponent() (ast.Expr, error) {
 	return primary, nil
 }
 
+// Parse function calls, indexing, member access, non-null assertions and error propagation
+func (p *Parser) call() (ast.Expr, error) {
+	expr, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.expect([]token.TokenType{token.LEFT_PAREN, token.LEFT_BRACKET, token.DOT, token.QUESTION_DOT, token.BANG_BANG, token.QUESTION}) {
+		switch p.previous().Kind {
+		case token.BANG_BANG:
+			expr = &ast.NonNullExpr{
+				Pos:  p.previous().Pos,
+				Expr: expr,
+			}
+			continue
+		case token.QUESTION:
+			expr = &ast.UnwrapExpr{
+				Pos:  p.previous().Pos,
+				Expr: expr,
+			}
+			continue
+		case token.LEFT_BRACKET:
+			lbracket := p.previous()
+
+			index, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = p.consume(token.RIGHT_BRACKET)
+			if err != nil {
+				return nil, err
+			}
+
+			expr = &ast.IndexExpr{
+				Pos:    lbracket.Pos,
+				Object: expr,
+				Index:  index,
+			}
+			continue
+		case token.DOT, token.QUESTION_DOT:
+			safe := p.previous().Kind == token.QUESTION_DOT
+			name, err := p.consume(token.IDENT)
+			if err != nil {
+				return nil, err
+			}
+
+			expr = &ast.GetExpr{
+				Pos:    name.Pos,
+				Object: expr,
+				Name:   name.Value,
+				Safe:   safe,
+			}
+			continue
+		}
+
+		args := []ast.Expr{}
+		labels := []string{}
+		if !p.check(token.RIGHT_PAREN) {
+			for {
+				// Named argument, e.g. 'x = 5'
+				label := ""
+				if p.check(token.IDENT) && p.peekNext().Kind == token.EQUAL {
+					label = p.advance().Value
+					p.advance()
+				}
+
+				arg, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+
+				args = append(args, arg)
+				labels = append(labels, label)
+
+				if !p.expect([]token.TokenType{token.COMMA}) {
+					break
+				}
+			}
+		}
+
+		_, err := p.consume(token.RIGHT_PAREN)
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.CallExpr{
+			Pos:    expr.Position(),
+			Callee: expr,
+			Args:   args,
+			Labels: labels,
+		}
+	}
+
+	return expr, nil
+}
+
 // Parse literals and groupings
 func (p *Parser) primary() (ast.Expr, error) {
 	if p.expect([]token.TokenType{token.LEFT_PAREN}) {
@@ -663,7 +2053,7 @@ func (p *Parser) primary() (ast.Expr, error) {
 		}, nil
 	}
 
-	literals := []token.TokenType{token.INTEGER, token.REAL, token.STRING, token.TRUE, token.FALSE}
+	literals := []token.TokenType{token.INTEGER, token.REAL, token.BIGINT, token.DECIMAL, token.STRING, token.CHAR, token.TRUE, token.FALSE, token.NULL}
 	if p.expect(literals) {
 		token := p.previous()
 
@@ -682,5 +2072,192 @@ func (p *Parser) primary() (ast.Expr, error) {
 		}, nil
 	}
 
+	if p.expect([]token.TokenType{token.THIS}) {
+		return &ast.ThisExpr{
+			Pos: p.previous().Pos,
+		}, nil
+	}
+
+	if p.expect([]token.TokenType{token.LEFT_BRACKET}) {
+		return p.list()
+	}
+
+	if p.expect([]token.TokenType{token.LEFT_BRACE}) {
+		return p.lambda()
+	}
+
 	return nil, p.error("Expected expression", p.peek())
 }
+
+// Parse list or map literal
+// Opening bracket is already consumed
+func (p *Parser) list() (ast.Expr, error) {
+	lbracket := p.previous()
+
+	// Empty map
+	if p.expect([]token.TokenType{token.COLON}) {
+		_, err := p.consume(token.RIGHT_BRACKET)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.MapLiteral{
+			Pos:    lbracket.Pos,
+			Keys:   []ast.Expr{},
+			Values: []ast.Expr{},
+		}, nil
+	}
+
+	elements := []ast.Expr{}
+	if !p.check(token.RIGHT_BRACKET) {
+		for {
+			element, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			// A colon after the first element makes this a map
+			if len(elements) == 0 && p.check(token.COLON) {
+				return p.mapEntries(lbracket, element)
+			}
+
+			elements = append(elements, element)
+
+			if !p.expect([]token.TokenType{token.COMMA}) {
+				break
+			}
+		}
+	}
+
+	_, err := p.consume(token.RIGHT_BRACKET)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ListLiteral{
+		Pos:      lbracket.Pos,
+		Elements: elements,
+	}, nil
+}
+
+// Parse entries of map literal starting with the key of the first entry
+func (p *Parser) mapEntries(lbracket token.Token, key ast.Expr) (ast.Expr, error) {
+	keys := []ast.Expr{}
+	values := []ast.Expr{}
+
+	for {
+		_, err := p.consume(token.COLON)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+
+		if !p.expect([]token.TokenType{token.COMMA}) {
+			break
+		}
+
+		key, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := p.consume(token.RIGHT_BRACKET)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.MapLiteral{
+		Pos:    lbracket.Pos,
+		Keys:   keys,
+		Values: values,
+	}, nil
+}
+
+// Parse lambda expression
+// Opening brace is already consumed
+//
+//	lambda ::= "{" ( ( lambdaParameter ( "," lambdaParameter )* )? "->" )? statement* "}";
+func (p *Parser) lambda() (ast.Expr, error) {
+	lbrace := p.previous()
+
+	params := []*ast.Parameter{}
+	if p.hasLambdaArrow() {
+		var err error
+		params, err = p.lambdaParameters()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(token.MINUS_GREATER)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	statements, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.LambdaExpr{
+		Pos:    lbrace.Pos,
+		Params: params,
+		Body: &ast.BlockExpr{
+			Pos:   lbrace.Pos,
+			Stmts: statements,
+		},
+	}, nil
+}
+
+// Parse parameters of lambda before '->'
+func (p *Parser) lambdaParameters() ([]*ast.Parameter, error) {
+	params := []*ast.Parameter{}
+	if p.check(token.MINUS_GREATER) {
+		return params, nil
+	}
+
+	for {
+		param, err := p.lambdaParameter()
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, param)
+
+		if !p.expect([]token.TokenType{token.COMMA}) {
+			return params, nil
+		}
+	}
+}
+
+// Look ahead for lambda parameters followed by '->'
+// Parameters are parsed without advancing or reporting errors
+func (p *Parser) hasLambdaArrow() bool {
+	current, errors := p.current, len(p.errors)
+	defer func() {
+		p.current = current
+		p.errors = p.errors[:errors]
+	}()
+
+	_, err := p.lambdaParameters()
+	return err == nil && p.check(token.MINUS_GREATER)
+}
+
+// Check if the brace at the current token starts a lambda with '->'
+func (p *Parser) lambdaAhead() bool {
+	current := p.current
+	defer func() {
+		p.current = current
+	}()
+
+	p.advance()
+	return p.hasLambdaArrow()
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"interpreter/ast"
+	"interpreter/diagnostic"
 	"interpreter/lexer"
 	"interpreter/token"
 	"testing"
@@ -140,6 +141,1116 @@ func TestLogicalOperators(t *testing.T) {
 	verifyLiteral(t, right, ast.LiteralExpr{Kind: token.TRUE, Value: "true"})
 }
 
+func TestCallExpression(t *testing.T) {
+	input := "add(1, 2)(3)"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	expr, err := parser.expression()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	outer := verifyExprType[*ast.CallExpr](t, expr)
+	if len(outer.Args) != 1 {
+		t.Fatalf("Unexpected number of arguments. Expected 1, found %d", len(outer.Args))
+	}
+
+	inner := verifyExprType[*ast.CallExpr](t, outer.Callee)
+	if len(inner.Args) != 2 {
+		t.Fatalf("Unexpected number of arguments. Expected 2, found %d", len(inner.Args))
+	}
+
+	callee := verifyExprType[*ast.Ident](t, inner.Callee)
+	if callee.Name != "add" {
+		t.Fatalf("Unexpected callee. Expected %q, found %q", "add", callee.Name)
+	}
+
+	arg := verifyExprType[*ast.LiteralExpr](t, inner.Args[1])
+	verifyLiteral(t, arg, ast.LiteralExpr{Kind: token.INTEGER, Value: "2"})
+}
+
+func TestFunctionDeclaration(t *testing.T) {
+	input := "fun add(a: int, b: int): int { return a + b; }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	if len(stmts) != 1 {
+		t.Fatalf("Unexpected number of statements. Expected 1, found %d", len(stmts))
+	}
+
+	decl, ok := stmts[0].(*ast.FunDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", decl, stmts[0])
+	}
+
+	if len(decl.Params) != 2 || decl.Params[0].Name != "a" || decl.Params[1].Name != "b" {
+		t.Fatalf("Unexpected parameters: %v", decl.Params)
+	}
+
+	return_type, ok := decl.ReturnType.(*ast.NamedType)
+	if !ok || return_type.Name != "int" {
+		t.Fatalf("Unexpected return type: %v", decl.ReturnType)
+	}
+
+	if len(decl.Body.Stmts) != 1 {
+		t.Fatalf("Unexpected number of statements in body. Expected 1, found %d", len(decl.Body.Stmts))
+	}
+
+	ret, ok := decl.Body.Stmts[0].(*ast.ReturnStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", ret, decl.Body.Stmts[0])
+	}
+
+	binary := verifyExprType[*ast.BinaryExpr](t, ret.Value)
+	verifyOperator(t, binary.Op, token.Token{Kind: token.PLUS})
+}
+
+func TestLambdaExpression(t *testing.T) {
+	input := "val add: (int, int) -> int = { a: int, b: int -> a + b };"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	decl, ok := stmts[0].(*ast.VarDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", decl, stmts[0])
+	}
+
+	function_type, ok := decl.Type.(*ast.FunctionType)
+	if !ok {
+		t.Fatalf("Unexpected type. Expected %T, found %T", function_type, decl.Type)
+	}
+
+	if len(function_type.Params) != 2 {
+		t.Fatalf("Unexpected number of parameter types. Expected 2, found %d", len(function_type.Params))
+	}
+
+	lambda := verifyExprType[*ast.LambdaExpr](t, decl.Value)
+	if len(lambda.Params) != 2 {
+		t.Fatalf("Unexpected number of parameters. Expected 2, found %d", len(lambda.Params))
+	}
+
+	if len(lambda.Body.Stmts) != 1 {
+		t.Fatalf("Unexpected number of statements in body. Expected 1, found %d", len(lambda.Body.Stmts))
+	}
+
+	body, ok := lambda.Body.Stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", body, lambda.Body.Stmts[0])
+	}
+
+	binary := verifyExprType[*ast.BinaryExpr](t, body.Expr)
+	verifyOperator(t, binary.Op, token.Token{Kind: token.PLUS})
+}
+
+func TestUntypedLambdaParameters(t *testing.T) {
+	input := "apply(1, { x, y: int -> x });"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	stmt, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[0])
+	}
+
+	call := verifyExprType[*ast.CallExpr](t, stmt.Expr)
+	lambda := verifyExprType[*ast.LambdaExpr](t, call.Args[1])
+	if len(lambda.Params) != 2 {
+		t.Fatalf("Unexpected number of parameters. Expected 2, found %d", len(lambda.Params))
+	}
+
+	if lambda.Params[0].Name != "x" || lambda.Params[0].Type != nil {
+		t.Errorf("Expected untyped parameter x, found %s with type %v", lambda.Params[0].Name, lambda.Params[0].Type)
+	}
+
+	if lambda.Params[1].Name != "y" || lambda.Params[1].Type == nil {
+		t.Errorf("Expected typed parameter y, found %s with type %v", lambda.Params[1].Name, lambda.Params[1].Type)
+	}
+}
+
+func TestLambdaReturningLambda(t *testing.T) {
+	input := "val k = { -> { -> 1 } }; val twice = { f: (int) -> int -> { x: int -> f(f(x)) } };"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	if len(stmts) != 2 {
+		t.Fatalf("Unexpected number of statements. Expected 2, found %d", len(stmts))
+	}
+
+	for n, params := range []int{0, 1} {
+		decl, ok := stmts[n].(*ast.VarDeclaration)
+		if !ok {
+			t.Fatalf("Unexpected statement type. Expected %T, found %T", decl, stmts[n])
+		}
+
+		lambda := verifyExprType[*ast.LambdaExpr](t, decl.Value)
+		if len(lambda.Params) != params {
+			t.Fatalf("Unexpected number of parameters. Expected %d, found %d", params, len(lambda.Params))
+		}
+
+		body, ok := lambda.Body.Stmts[0].(*ast.ExprStmt)
+		if !ok {
+			t.Fatalf("Unexpected statement type. Expected %T, found %T", body, lambda.Body.Stmts[0])
+		}
+
+		inner := verifyExprType[*ast.LambdaExpr](t, body.Expr)
+		if len(inner.Params) != params {
+			t.Fatalf("Unexpected number of inner parameters. Expected %d, found %d", params, len(inner.Params))
+		}
+	}
+}
+
+func TestNullableFunctionType(t *testing.T) {
+	input := "var f: (() -> int)? = null;"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	decl, ok := stmts[0].(*ast.VarDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", decl, stmts[0])
+	}
+
+	nullable, ok := decl.Type.(*ast.NullableType)
+	if !ok {
+		t.Fatalf("Unexpected type. Expected %T, found %T", nullable, decl.Type)
+	}
+
+	function_type, ok := nullable.Elem.(*ast.FunctionType)
+	if !ok {
+		t.Fatalf("Unexpected type. Expected %T, found %T", function_type, nullable.Elem)
+	}
+
+	if len(function_type.Params) != 0 {
+		t.Fatalf("Unexpected number of parameter types. Expected 0, found %d", len(function_type.Params))
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	input := "for i in 0..<10 step 2 { i; }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	loop, ok := stmts[0].(*ast.ForStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", loop, stmts[0])
+	}
+
+	if loop.Name != "i" {
+		t.Fatalf("Unexpected loop variable. Expected %q, found %q", "i", loop.Name)
+	}
+
+	iterable := verifyExprType[*ast.RangeExpr](t, loop.Iterable)
+	if iterable.Inclusive {
+		t.Fatalf("Expected exclusive range")
+	}
+
+	start := verifyExprType[*ast.LiteralExpr](t, iterable.Start)
+	verifyLiteral(t, start, ast.LiteralExpr{Kind: token.INTEGER, Value: "0"})
+
+	end := verifyExprType[*ast.LiteralExpr](t, iterable.End)
+	verifyLiteral(t, end, ast.LiteralExpr{Kind: token.INTEGER, Value: "10"})
+
+	step := verifyExprType[*ast.LiteralExpr](t, iterable.Step)
+	verifyLiteral(t, step, ast.LiteralExpr{Kind: token.INTEGER, Value: "2"})
+}
+
+func TestLabeledLoop(t *testing.T) {
+	input := "outer@ while true { for i in 0..1 { break@outer; continue; } }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	loop, ok := stmts[0].(*ast.WhileStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", loop, stmts[0])
+	}
+
+	if loop.Label != "outer" {
+		t.Fatalf("Unexpected label. Expected %q, found %q", "outer", loop.Label)
+	}
+
+	inner, ok := loop.Block.Stmts[0].(*ast.ForStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", inner, loop.Block.Stmts[0])
+	}
+
+	if inner.Label != "" {
+		t.Fatalf("Unexpected label. Expected no label, found %q", inner.Label)
+	}
+
+	brk, ok := inner.Block.Stmts[0].(*ast.BreakStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", brk, inner.Block.Stmts[0])
+	}
+
+	if brk.Label != "outer" {
+		t.Fatalf("Unexpected label. Expected %q, found %q", "outer", brk.Label)
+	}
+
+	cont, ok := inner.Block.Stmts[1].(*ast.ContinueStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", cont, inner.Block.Stmts[1])
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	input := "match n { 0 -> fall, -1..<10 -> 1, x if x > 10 -> { x }, _ -> 2 }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	expr, err := parser.expression()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	match := verifyExprType[*ast.MatchExpr](t, expr)
+	if len(match.Arms) != 4 {
+		t.Fatalf("Unexpected number of arms. Expected 4, found %d", len(match.Arms))
+	}
+
+	if _, ok := match.Arms[0].Pattern.(*ast.LiteralPattern); !ok || !match.Arms[0].Fall {
+		t.Fatalf("Expected literal pattern falling through, found %T", match.Arms[0].Pattern)
+	}
+
+	bounds, ok := match.Arms[1].Pattern.(*ast.RangePattern)
+	if !ok {
+		t.Fatalf("Unexpected pattern type. Expected %T, found %T", bounds, match.Arms[1].Pattern)
+	}
+	verifyLiteral(t, bounds.Start, ast.LiteralExpr{Kind: token.INTEGER, Value: "-1"})
+	verifyLiteral(t, bounds.End, ast.LiteralExpr{Kind: token.INTEGER, Value: "10"})
+
+	binding, ok := match.Arms[2].Pattern.(*ast.BindingPattern)
+	if !ok || binding.Name != "x" {
+		t.Fatalf("Expected binding of x, found %#v", match.Arms[2].Pattern)
+	}
+	verifyExprType[*ast.BinaryExpr](t, match.Arms[2].Guard)
+	verifyExprType[*ast.BlockExpr](t, match.Arms[2].Body)
+
+	if _, ok := match.Arms[3].Pattern.(*ast.WildcardPattern); !ok {
+		t.Fatalf("Expected wildcard pattern, found %T", match.Arms[3].Pattern)
+	}
+}
+
+func TestCompoundAssignment(t *testing.T) {
+	input := "x += 1; y ~= 2; z = 3;"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	expected := []token.TokenType{token.PLUS_EQUAL, token.TILDE_EQUAL, token.EQUAL}
+	if len(stmts) != len(expected) {
+		t.Fatalf("Unexpected number of statements. Expected %d, found %d", len(expected), len(stmts))
+	}
+
+	for i, stmt := range stmts {
+		assignment, ok := stmt.(*ast.AssignmentStmt)
+		if !ok {
+			t.Fatalf("Unexpected statement type. Expected %T, found %T", assignment, stmt)
+		}
+
+		verifyOperator(t, assignment.Op, token.Token{Kind: expected[i]})
+	}
+}
+
+func TestListExpression(t *testing.T) {
+	input := "var xs: List<[int]> = [[1, 2], []]; xs[0][1] = xs.length;"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	decl, ok := stmts[0].(*ast.VarDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", decl, stmts[0])
+	}
+
+	named, ok := decl.Type.(*ast.NamedType)
+	if !ok || named.Name != "List" || len(named.Args) != 1 {
+		t.Fatalf("Unexpected type. Expected List with 1 type argument, found %#v", decl.Type)
+	}
+
+	if _, ok := named.Args[0].(*ast.ListType); !ok {
+		t.Fatalf("Unexpected type argument. Expected %T, found %T", &ast.ListType{}, named.Args[0])
+	}
+
+	list := verifyExprType[*ast.ListLiteral](t, decl.Value)
+	if len(list.Elements) != 2 {
+		t.Fatalf("Unexpected number of elements. Expected 2, found %d", len(list.Elements))
+	}
+
+	verifyExprType[*ast.ListLiteral](t, list.Elements[1])
+
+	assignment, ok := stmts[1].(*ast.IndexAssignmentStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", assignment, stmts[1])
+	}
+
+	inner := verifyExprType[*ast.IndexExpr](t, assignment.Object)
+	verifyExprType[*ast.Ident](t, inner.Object)
+	verifyLiteral(t, verifyExprType[*ast.LiteralExpr](t, assignment.Index), ast.LiteralExpr{Kind: token.INTEGER, Value: "1"})
+
+	get := verifyExprType[*ast.GetExpr](t, assignment.Value)
+	if get.Name != "length" {
+		t.Fatalf("Unexpected member. Expected length, found %s", get.Name)
+	}
+}
+
+func TestMapExpression(t *testing.T) {
+	input := "var m: [string: int] = [\"a\": 1, \"b\": 2]; m = [:]; for (k, v) in m { k; }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	decl, ok := stmts[0].(*ast.VarDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", decl, stmts[0])
+	}
+
+	if _, ok := decl.Type.(*ast.MapType); !ok {
+		t.Fatalf("Unexpected type. Expected %T, found %T", &ast.MapType{}, decl.Type)
+	}
+
+	literal := verifyExprType[*ast.MapLiteral](t, decl.Value)
+	if len(literal.Keys) != 2 || len(literal.Values) != 2 {
+		t.Fatalf("Unexpected number of entries. Expected 2, found %d", len(literal.Keys))
+	}
+
+	verifyLiteral(t, verifyExprType[*ast.LiteralExpr](t, literal.Keys[1]), ast.LiteralExpr{Kind: token.STRING, Value: "b"})
+	verifyLiteral(t, verifyExprType[*ast.LiteralExpr](t, literal.Values[1]), ast.LiteralExpr{Kind: token.INTEGER, Value: "2"})
+
+	assignment, ok := stmts[1].(*ast.AssignmentStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", assignment, stmts[1])
+	}
+
+	empty := verifyExprType[*ast.MapLiteral](t, assignment.Value)
+	if len(empty.Keys) != 0 {
+		t.Fatalf("Unexpected number of entries. Expected 0, found %d", len(empty.Keys))
+	}
+
+	loop, ok := stmts[2].(*ast.ForStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", loop, stmts[2])
+	}
+
+	if loop.Name != "k" || loop.Value != "v" {
+		t.Fatalf("Unexpected loop variables. Expected (k, v), found (%s, %s)", loop.Name, loop.Value)
+	}
+}
+
+func TestClassDeclaration(t *testing.T) {
+	input := "class Point(val x: int, var y: int) { fun move(d: int) { this.y += d; } }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	class, ok := stmts[0].(*ast.ClassDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", class, stmts[0])
+	}
+
+	if class.Name != "Point" {
+		t.Fatalf("Unexpected class name. Expected Point, found %s", class.Name)
+	}
+
+	expected := []token.TokenType{token.VAL, token.VAR}
+	if len(class.Fields) != len(expected) {
+		t.Fatalf("Unexpected number of fields. Expected %d, found %d", len(expected), len(class.Fields))
+	}
+
+	for i, field := range class.Fields {
+		if field.DeclType != expected[i] {
+			t.Fatalf("Unexpected field declaration. Expected %v, found %v", expected[i], field.DeclType)
+		}
+	}
+
+	if len(class.Methods) != 1 {
+		t.Fatalf("Unexpected number of methods. Expected 1, found %d", len(class.Methods))
+	}
+
+	assignment, ok := class.Methods[0].Body.Stmts[0].(*ast.FieldAssignmentStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", assignment, class.Methods[0].Body.Stmts[0])
+	}
+
+	verifyExprType[*ast.ThisExpr](t, assignment.Object)
+	verifyOperator(t, assignment.Op, token.Token{Kind: token.PLUS_EQUAL})
+}
+
+func TestDataClass(t *testing.T) {
+	input := "data class Point(val x: int, val y: int) p.copy(y = 2);"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	class, ok := stmts[0].(*ast.ClassDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", class, stmts[0])
+	}
+
+	if !class.Data {
+		t.Fatalf("Expected data class")
+	}
+
+	stmt, ok := stmts[1].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[1])
+	}
+
+	call := verifyExprType[*ast.CallExpr](t, stmt.Expr)
+	get := verifyExprType[*ast.GetExpr](t, call.Callee)
+	if get.Name != "copy" {
+		t.Fatalf("Unexpected member. Expected copy, found %s", get.Name)
+	}
+
+	if len(call.Labels) != 1 || call.Labels[0] != "y" {
+		t.Fatalf("Unexpected argument labels. Expected [y], found %v", call.Labels)
+	}
+}
+
+func TestEnumDeclaration(t *testing.T) {
+	input := "enum Shape { Circle(r: real), Rect(w: real, h: real), Empty, } match s { Shape.Rect(w, _) -> w, _ -> 0.0 }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	enum, ok := stmts[0].(*ast.EnumDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", enum, stmts[0])
+	}
+
+	expected := []int{1, 2, 0}
+	if len(enum.Variants) != len(expected) {
+		t.Fatalf("Unexpected number of variants. Expected %d, found %d", len(expected), len(enum.Variants))
+	}
+
+	for i, variant := range enum.Variants {
+		if len(variant.Fields) != expected[i] {
+			t.Fatalf("Unexpected number of fields in %s. Expected %d, found %d", variant.Name, expected[i], len(variant.Fields))
+		}
+	}
+
+	stmt, ok := stmts[1].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[1])
+	}
+
+	match := verifyExprType[*ast.MatchExpr](t, stmt.Expr)
+	pattern, ok := match.Arms[0].Pattern.(*ast.VariantPattern)
+	if !ok {
+		t.Fatalf("Unexpected pattern type. Expected %T, found %T", pattern, match.Arms[0].Pattern)
+	}
+
+	if pattern.Enum != "Shape" || pattern.Variant != "Rect" || len(pattern.Fields) != 2 {
+		t.Fatalf("Unexpected variant pattern. Expected Shape.Rect with 2 fields, found %s.%s with %d", pattern.Enum, pattern.Variant, len(pattern.Fields))
+	}
+
+	if _, ok := pattern.Fields[1].(*ast.WildcardPattern); !ok {
+		t.Fatalf("Unexpected pattern type. Expected %T, found %T", &ast.WildcardPattern{}, pattern.Fields[1])
+	}
+}
+
+func TestNullableExpression(t *testing.T) {
+	input := "var x: int? = null; a?.b ?: c ?: 0; x!! + 1;"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	decl, ok := stmts[0].(*ast.VarDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", decl, stmts[0])
+	}
+
+	if _, ok := decl.Type.(*ast.NullableType); !ok {
+		t.Fatalf("Unexpected type. Expected %T, found %T", &ast.NullableType{}, decl.Type)
+	}
+
+	verifyLiteral(t, verifyExprType[*ast.LiteralExpr](t, decl.Value), ast.LiteralExpr{Kind: token.NULL, Value: "null"})
+
+	// Elvis is right associative: a?.b ?: (c ?: 0)
+	stmt, ok := stmts[1].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[1])
+	}
+
+	elvis := verifyExprType[*ast.ElvisExpr](t, stmt.Expr)
+	get := verifyExprType[*ast.GetExpr](t, elvis.Left)
+	if !get.Safe {
+		t.Fatalf("Expected safe member access")
+	}
+
+	inner := verifyExprType[*ast.ElvisExpr](t, elvis.Right)
+	verifyExprType[*ast.Ident](t, inner.Left)
+
+	// Non-null assertion binds tighter than binary operators
+	stmt, ok = stmts[2].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[2])
+	}
+
+	binary := verifyExprType[*ast.BinaryExpr](t, stmt.Expr)
+	verifyExprType[*ast.NonNullExpr](t, binary.Left)
+}
+
+func TestGenericDeclaration(t *testing.T) {
+	input := "fun <T> first(xs: [T]): T { return xs[0]; } class Pair<A, B>(val a: A, val b: Pair<B, A>?)"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	fun, ok := stmts[0].(*ast.FunDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", fun, stmts[0])
+	}
+
+	if len(fun.TypeParams) != 1 || fun.TypeParams[0].Name != "T" {
+		t.Fatalf("Unexpected type parameters. Expected [T], found %v", fun.TypeParams)
+	}
+
+	class, ok := stmts[1].(*ast.ClassDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", class, stmts[1])
+	}
+
+	if len(class.TypeParams) != 2 {
+		t.Fatalf("Unexpected number of type parameters. Expected 2, found %d", len(class.TypeParams))
+	}
+
+	nullable, ok := class.Fields[1].Type.(*ast.NullableType)
+	if !ok {
+		t.Fatalf("Unexpected type. Expected %T, found %T", nullable, class.Fields[1].Type)
+	}
+
+	named, ok := nullable.Elem.(*ast.NamedType)
+	if !ok || named.Name != "Pair" || len(named.Args) != 2 {
+		t.Fatalf("Unexpected type. Expected Pair<B, A>, found %#v", nullable.Elem)
+	}
+}
+
+func TestInterfaceDeclaration(t *testing.T) {
+	input := "interface Shape { fun area(): real fun scale(k: real): Shape; } class Square(val side: real) : Shape, Named {}"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Log("Expected no lexer errors")
+
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	iface, ok := stmts[0].(*ast.InterfaceDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", iface, stmts[0])
+	}
+
+	if len(iface.Methods) != 2 {
+		t.Fatalf("Unexpected number of methods. Expected 2, found %d", len(iface.Methods))
+	}
+
+	for _, method := range iface.Methods {
+		if method.Body != nil {
+			t.Fatalf("Unexpected body of interface method %s", method.Name)
+		}
+	}
+
+	class, ok := stmts[1].(*ast.ClassDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", class, stmts[1])
+	}
+
+	expected := []string{"Shape", "Named"}
+	if len(class.Interfaces) != len(expected) {
+		t.Fatalf("Unexpected number of interfaces. Expected %d, found %d", len(expected), len(class.Interfaces))
+	}
+
+	for i, name := range expected {
+		if class.Interfaces[i].Name != name {
+			t.Fatalf("Unexpected interface. Expected %s, found %s", name, class.Interfaces[i].Name)
+		}
+	}
+}
+
+func TestIsExpression(t *testing.T) {
+	input := "x is int? && y < 2;"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	stmt, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[0])
+	}
+
+	logical := verifyExprType[*ast.LogicalExpr](t, stmt.Expr)
+	verifyOperator(t, logical.Op, token.Token{Kind: token.LAND})
+
+	is := verifyExprType[*ast.IsExpr](t, logical.Left)
+	ident := verifyExprType[*ast.Ident](t, is.Expr)
+	if ident.Name != "x" {
+		t.Errorf("Expected identifier x, found %s", ident.Name)
+	}
+
+	nullable, ok := is.Type.(*ast.NullableType)
+	if !ok {
+		t.Fatalf("Unexpected type. Expected %T, found %T", nullable, is.Type)
+	}
+
+	verifyExprType[*ast.BinaryExpr](t, logical.Right)
+}
+
+func TestCastExpression(t *testing.T) {
+	input := "2 * x as? real + y as int;"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	stmt, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[0])
+	}
+
+	// Casts bind tighter than multiplication
+	sum := verifyExprType[*ast.BinaryExpr](t, stmt.Expr)
+	verifyOperator(t, sum.Op, token.Token{Kind: token.PLUS})
+
+	product := verifyExprType[*ast.BinaryExpr](t, sum.Left)
+	verifyOperator(t, product.Op, token.Token{Kind: token.STAR})
+
+	safe := verifyExprType[*ast.CastExpr](t, product.Right)
+	if !safe.Safe {
+		t.Errorf("Expected safe cast")
+	}
+
+	unsafe := verifyExprType[*ast.CastExpr](t, sum.Right)
+	if unsafe.Safe {
+		t.Errorf("Expected unsafe cast")
+	}
+
+	named, ok := unsafe.Type.(*ast.NamedType)
+	if !ok {
+		t.Fatalf("Unexpected type. Expected %T, found %T", named, unsafe.Type)
+	}
+
+	if named.Name != "int" {
+		t.Errorf("Expected type int, found %s", named.Name)
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	input := "val x = try { f() } catch (e: ParseError) { 0 } catch (e: Error) { 1 } finally { g() }; throw e; try { f() }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+
+	// Try without catch or finally is an error
+	if len(errors) != 1 {
+		t.Fatalf("Expected 1 error, found %v", errors)
+	}
+
+	if len(stmts) != 2 {
+		t.Fatalf("Expected 2 statements, found %d", len(stmts))
+	}
+
+	decl, ok := stmts[0].(*ast.VarDeclaration)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", decl, stmts[0])
+	}
+
+	try := verifyExprType[*ast.TryExpr](t, decl.Value)
+	if len(try.Catches) != 2 {
+		t.Fatalf("Expected 2 catch clauses, found %d", len(try.Catches))
+	}
+
+	for n, expected := range []string{"ParseError", "Error"} {
+		catch := try.Catches[n]
+		if catch.Name != "e" {
+			t.Errorf("Expected catch of e, found %s", catch.Name)
+		}
+
+		named, ok := catch.Type.(*ast.NamedType)
+		if !ok {
+			t.Fatalf("Unexpected type. Expected %T, found %T", named, catch.Type)
+		}
+
+		if named.Name != expected {
+			t.Errorf("Expected type %s, found %s", expected, named.Name)
+		}
+	}
+
+	if try.Finally == nil {
+		t.Errorf("Expected finally block")
+	}
+
+	throw, ok := stmts[1].(*ast.ThrowStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", throw, stmts[1])
+	}
+
+	verifyExprType[*ast.Ident](t, throw.Value)
+}
+
+func TestResultExpression(t *testing.T) {
+	input := "f(x)?.length; f(x)? + g()?; match r { Ok(v) -> v, Err(_) -> 0 }"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	parser := NewParser(tokens, "test")
+	stmts, errors := parser.Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	// '?.' is safe member access, not unwrapping
+	stmt, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[0])
+	}
+
+	get := verifyExprType[*ast.GetExpr](t, stmt.Expr)
+	if !get.Safe {
+		t.Errorf("Expected safe access")
+	}
+
+	stmt, ok = stmts[1].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[1])
+	}
+
+	sum := verifyExprType[*ast.BinaryExpr](t, stmt.Expr)
+	for _, operand := range []ast.Expr{sum.Left, sum.Right} {
+		unwrap := verifyExprType[*ast.UnwrapExpr](t, operand)
+		verifyExprType[*ast.CallExpr](t, unwrap.Expr)
+	}
+
+	stmt, ok = stmts[2].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("Unexpected statement type. Expected %T, found %T", stmt, stmts[2])
+	}
+
+	match := verifyExprType[*ast.MatchExpr](t, stmt.Expr)
+	for n, expected := range []string{"Ok", "Err"} {
+		pattern, ok := match.Arms[n].Pattern.(*ast.VariantPattern)
+		if !ok {
+			t.Fatalf("Unexpected pattern type. Expected %T, found %T", pattern, match.Arms[n].Pattern)
+		}
+
+		if pattern.Enum != "" || pattern.Variant != expected || len(pattern.Fields) != 1 {
+			t.Fatalf("Unexpected variant pattern. Expected %s with 1 field, found %s.%s with %d", expected, pattern.Enum, pattern.Variant, len(pattern.Fields))
+		}
+	}
+}
+
+func TestMissingSemicolon(t *testing.T) {
+	input := "val x = 1\nval y = 2;"
+
+	lexer := lexer.NewLexer([]byte(input), "test")
+	tokens, errors := lexer.Tokenize()
+	if len(errors) != 0 {
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+
+		t.FailNow()
+	}
+
+	parser := NewParser(tokens, "test")
+	_, errors = parser.Parse()
+	if len(errors) == 0 {
+		t.Fatalf("Expected error for missing semicolon")
+	}
+
+	err, ok := errors[0].(*diagnostic.Diagnostic)
+	if !ok {
+		t.Fatalf("Unexpected error type. Expected %T, found %T", err, errors[0])
+	}
+
+	if err.Span.Start.Row != 2 || err.Span.Start.Column != 1 {
+		t.Errorf("Unexpected error position. Expected 2:1, found %d:%d", err.Span.Start.Row, err.Span.Start.Column)
+	}
+
+	// Semicolon should be inserted right after the value
+	if len(err.FixIts) != 1 {
+		t.Fatalf("Unexpected number of fix-its. Expected 1, found %d", len(err.FixIts))
+	}
+
+	fix := err.FixIts[0]
+	if fix.Replacement != ";" || fix.Span.Start.Row != 1 || fix.Span.Start.Column != 10 || fix.Span.End != fix.Span.Start {
+		t.Errorf("Unexpected fix-it. Expected to insert ';' at 1:10, found %q at %d:%d", fix.Replacement, fix.Span.Start.Row, fix.Span.Start.Column)
+	}
+}
+
 func verifyExprType[T ast.Expr](t *testing.T, expr ast.Expr) T {
 	var expected T
 	node, ok := expr.(T)
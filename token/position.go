@@ -7,9 +7,20 @@ import (
 type Position struct {
 	Row    int
 	Column int
+	Offset int // Byte offset in source
+	Length int // Number of bytes spanned, 0 if unknown
 }
 
 func (pos Position) String() string {
 	s := fmt.Sprintf("Row: %d, col: %d\n", pos.Row, pos.Column)
 	return s
 }
+
+// Get position right after the end of pos
+func (pos Position) End() Position {
+	return Position{
+		Row:    pos.Row,
+		Column: pos.Column + pos.Length,
+		Offset: pos.Offset + pos.Length,
+	}
+}
@@ -34,6 +34,9 @@ const (
 	SEMICOLON                      // ;
 	COLON                          // :
 	UNDERSCORE                     // _
+	DOT                            // .
+	AT                             // @
+	QUESTION                       // ?
 
 	// Operators (1-3 characters)
 	PLUS            // +
@@ -49,6 +52,7 @@ const (
 	STAR_STAR_EQUAL // **=
 	BANG            // !
 	BANG_EQUAL      // !=
+	BANG_BANG       // !!
 	EQUAL           // =
 	EQUAL_EQUAL     // ==
 	GREATER         // >
@@ -56,6 +60,10 @@ const (
 	LESS            // <
 	LESS_EQUAL      // <=
 	PERCENT         // %
+	DOT_DOT         // ..
+	DOT_DOT_LESS    // ..<
+	QUESTION_DOT    // ?.
+	QUESTION_COLON  // ?:
 
 	LAND        // &&
 	LOR         // ||
@@ -74,22 +82,36 @@ const (
 	CHAR
 	INTEGER
 	REAL
+	BIGINT  // 123n
+	DECIMAL // 1.10d
 
 	// Keywords
-	IF       // if
-	ELSE     // else
-	FALSE    // false
-	TRUE     // true
-	FOR      // for
-	IN       // in
-	WHILE    // while
-	FUN      // fun
-	RETURN   // return
-	VAL      // val
-	VAR      // var
-	CONTINUE // continue
-	FALL     // fall
-	MATCH    // match
+	IF        // if
+	ELSE      // else
+	FALSE     // false
+	TRUE      // true
+	FOR       // for
+	IN        // in
+	WHILE     // while
+	FUN       // fun
+	RETURN    // return
+	VAL       // val
+	VAR       // var
+	CONTINUE  // continue
+	FALL      // fall
+	MATCH     // match
+	BREAK     // break
+	CLASS     // class
+	THIS      // this
+	ENUM      // enum
+	NULL      // null
+	INTERFACE // interface
+	IS        // is
+	AS        // as
+	TRY       // try
+	CATCH     // catch
+	FINALLY   // finally
+	THROW     // throw
 
 	EOF
 	ILLEGAL
@@ -101,24 +123,48 @@ func (t TokenType) String() string {
 		return "'&'"
 	case AND_EQUAL:
 		return "'&='"
+	case AS:
+		return "'as'"
+	case AT:
+		return "'@'"
 	case BANG:
 		return "'!'"
 	case BANG_EQUAL:
 		return "'!="
+	case BANG_BANG:
+		return "'!!'"
+	case BIGINT:
+		return "bigint"
+	case BREAK:
+		return "'break'"
+	case CATCH:
+		return "'catch'"
 	case CARET:
 		return "'^'"
 	case CARET_EQUAL:
 		return "''^="
 	case CHAR:
 		return "char"
+	case CLASS:
+		return "'class'"
 	case COLON:
 		return "':'"
 	case COMMA:
 		return "','"
 	case CONTINUE:
 		return "'continue'"
+	case DECIMAL:
+		return "decimal"
+	case DOT:
+		return "'.'"
+	case DOT_DOT:
+		return "'..'"
+	case DOT_DOT_LESS:
+		return "'..<'"
 	case ELSE:
 		return "'else'"
+	case ENUM:
+		return "'enum'"
 	case EOF:
 		return "'EOF'"
 	case EQUAL:
@@ -129,6 +175,8 @@ func (t TokenType) String() string {
 		return "'fall'"
 	case FALSE:
 		return "'false'"
+	case FINALLY:
+		return "'finally'"
 	case FOR:
 		return "'for'"
 	case FUN:
@@ -145,8 +193,12 @@ func (t TokenType) String() string {
 		return "illegal token"
 	case IN:
 		return "'in'"
+	case INTERFACE:
+		return "'interface'"
 	case INTEGER:
 		return "integer"
+	case IS:
+		return "'is'"
 	case LAND:
 		return "'&&'"
 	case LEFT_BRACE:
@@ -169,6 +221,8 @@ func (t TokenType) String() string {
 		return "'-='"
 	case MINUS_GREATER:
 		return "'->'"
+	case NULL:
+		return "'null'"
 	case OR:
 		return "'|'"
 	case OR_EQUAL:
@@ -177,10 +231,18 @@ func (t TokenType) String() string {
 		return "'+'"
 	case PLUS_EQUAL:
 		return "'+='"
+	case QUESTION:
+		return "'?'"
+	case QUESTION_COLON:
+		return "'?:'"
+	case QUESTION_DOT:
+		return "'?.'"
 	case REAL:
 		return "real"
 	case RETURN:
 		return "'return'"
+	case THIS:
+		return "'this'"
 	case RIGHT_BRACE:
 		return "'}'"
 	case RIGHT_BRACKET:
@@ -203,12 +265,16 @@ func (t TokenType) String() string {
 		return "'**='"
 	case STRING:
 		return "string"
+	case THROW:
+		return "'throw'"
 	case TILDE:
 		return "'~'"
 	case TILDE_EQUAL:
 		return "'~='"
 	case TRUE:
 		return "'true'"
+	case TRY:
+		return "'try'"
 	case UNDERSCORE:
 		return "'_'"
 	case VAL:
@@ -223,3 +289,41 @@ func (t TokenType) String() string {
 
 	panic(fmt.Sprintf("Unexpected token.TokenType: %#v", t))
 }
+
+// Get binary operator applied by compound assignment operator
+// Returns false if kind is not a compound assignment
+func CompoundOperator(kind TokenType) (TokenType, bool) {
+	switch kind {
+	case PLUS_EQUAL:
+		return PLUS, true
+	case MINUS_EQUAL:
+		return MINUS, true
+	case STAR_EQUAL:
+		return STAR, true
+	case SLASH_EQUAL:
+		return SLASH, true
+	case STAR_STAR_EQUAL:
+		return STAR_STAR, true
+	case AND_EQUAL:
+		return AND, true
+	case OR_EQUAL:
+		return OR, true
+	case CARET_EQUAL:
+		return CARET, true
+	case TILDE_EQUAL:
+		// Bit clear: a ~= b is a = a & ~b
+		return TILDE, true
+	default:
+		return kind, false
+	}
+}
+
+// Check if kind is a number literal
+func IsNumber(kind TokenType) bool {
+	switch kind {
+	case INTEGER, REAL, BIGINT, DECIMAL:
+		return true
+	default:
+		return false
+	}
+}
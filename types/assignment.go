@@ -0,0 +1,109 @@
+package types
+
+// Assignment state of variables declared without a value at a point in the program
+type assignments map[*variable]assignState
+
+type assignState struct {
+	definitely bool // Assigned on every path to the point
+	possibly   bool // Assigned on some path to the point
+}
+
+// Start tracking assignments of variable declared without a value
+func (c *Checker) track(v *variable) {
+	c.unassigned = append(c.unassigned, v)
+}
+
+// Save assignment state of tracked variables
+func (c *Checker) saveAssignments() assignments {
+	saved := assignments{}
+	for _, v := range c.unassigned {
+		saved[v] = assignState{
+			definitely: v.initialized,
+			possibly:   v.assigned,
+		}
+	}
+
+	return saved
+}
+
+// Restore saved assignment state
+func (c *Checker) restoreAssignments(saved assignments) {
+	for v, state := range saved {
+		v.initialized, v.assigned = state.definitely, state.possibly
+	}
+}
+
+// Combine assignment states of two paths that meet
+// nil stands for a path that never reaches the meeting point
+func mergeAssignments(a assignments, b assignments) assignments {
+	if a == nil {
+		return b
+	}
+
+	if b == nil {
+		return a
+	}
+
+	merged := assignments{}
+	for v, x := range a {
+		// Variables declared on only one of the paths are out of scope
+		y, ok := b[v]
+		if !ok {
+			continue
+		}
+
+		merged[v] = assignState{
+			definitely: x.definitely && y.definitely,
+			possibly:   x.possibly || y.possibly,
+		}
+	}
+
+	return merged
+}
+
+// Combine assignment state of paths that complete with the state after a block
+// that runs after all of them, such as a finally block
+// Variables assigned on the paths or in the block are assigned after both
+func followAssignments(completed assignments, after assignments) assignments {
+	if completed == nil {
+		return nil
+	}
+
+	merged := assignments{}
+	for v, x := range completed {
+		y, ok := after[v]
+		if !ok {
+			continue
+		}
+
+		merged[v] = assignState{
+			definitely: x.definitely || y.definitely,
+			possibly:   x.possibly || y.possibly,
+		}
+	}
+
+	return merged
+}
+
+// Mark variables assigned in a loop body as possibly assigned,
+// as every iteration but the first runs after an earlier one
+func (c *Checker) assignedByLoop(names map[string]bool) {
+	for name := range names {
+		if v, ok := c.context.lookup(name).(*variable); ok {
+			v.declared().assigned = true
+		}
+	}
+}
+
+// Restore assignment state after a loop, where nil stands for a loop that never ends
+// Code after such a loop is never reached, so every variable counts as assigned
+func (c *Checker) restoreAfterLoop(after assignments) {
+	if after == nil {
+		for _, v := range c.unassigned {
+			v.initialized = true
+		}
+		return
+	}
+
+	c.restoreAssignments(after)
+}
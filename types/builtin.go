@@ -0,0 +1,50 @@
+package types
+
+// Functions implemented by the interpreter, available in every program
+func getBuiltins() map[string]symbol {
+	builtins := map[string]symbol{}
+
+	functions := map[string]*Function{
+		"parseInt":  NewFunction([]Type{NewString()}, NewInteger()),
+		"parseReal": NewFunction([]Type{NewString()}, NewReal()),
+	}
+
+	// Results are created by generic constructors, e.g. Ok(1) is a Result<int, E> for any E
+	value, err := NewTypeVar("T"), NewTypeVar("E")
+	for name, arg := range map[string]Type{"Ok": value, "Err": err} {
+		f := NewFunction([]Type{arg}, NewResult(value, err))
+		f.TypeParams = []*TypeVar{value, err}
+		functions[name] = f
+	}
+
+	for name, f := range functions {
+		builtins[name] = &variable{
+			name:        name,
+			kind:        f,
+			initialized: true,
+			assigned:    true,
+		}
+	}
+
+	return builtins
+}
+
+// Singleton interface implemented by thrown values
+var errorInterface *Interface = nil
+
+// Get interface of errors, with a message describing the error
+func NewError() *Interface {
+	if errorInterface == nil {
+		errorInterface = NewInterface("Error")
+		errorInterface.Methods["message"] = NewFunction([]Type{}, NewString())
+	}
+
+	return errorInterface
+}
+
+// Types defined by the interpreter, available in every program
+func getBuiltinTypes() map[string]Type {
+	return map[string]Type{
+		"Error": NewError(),
+	}
+}
@@ -0,0 +1,191 @@
+package types
+
+import "interpreter/ast"
+
+// Finds variables assigned in lambdas and functions nested in the scope of the variable
+// Smart casts of these variables are unsound, as any call may reassign them
+type captureFinder struct {
+	scopes   []*captureScope
+	depth    int                          // Number of enclosing functions and lambdas
+	captured map[*ast.VarDeclaration]bool // Declarations of variables assigned in nested functions
+	outside  map[string]bool              // Names assigned in functions but declared outside the program, e.g. on earlier lines of the REPL
+}
+
+// Variables declared in block
+type captureScope struct {
+	depth int
+	vars  map[string]*ast.VarDeclaration
+}
+
+// Find variables assigned in lambdas and functions nested in their scope
+func findCaptured(program []ast.Stmt) *captureFinder {
+	f := &captureFinder{
+		scopes:   []*captureScope{},
+		captured: map[*ast.VarDeclaration]bool{},
+		outside:  map[string]bool{},
+	}
+	f.block(program)
+
+	return f
+}
+
+// Visit statements in a new scope
+func (f *captureFinder) block(stmts []ast.Stmt) {
+	f.scopes = append(f.scopes, &captureScope{
+		depth: f.depth,
+		vars:  map[string]*ast.VarDeclaration{},
+	})
+
+	for _, stmt := range stmts {
+		f.visit(stmt)
+	}
+
+	f.scopes = f.scopes[:len(f.scopes)-1]
+}
+
+// Visit body of function or lambda, where parameters shadow outer variables
+func (f *captureFinder) function(params []*ast.Parameter, body []ast.Stmt) {
+	f.depth++
+	f.scopes = append(f.scopes, &captureScope{
+		depth: f.depth,
+		vars:  map[string]*ast.VarDeclaration{},
+	})
+	for _, param := range params {
+		f.scopes[len(f.scopes)-1].vars[param.Name] = nil
+	}
+
+	f.block(body)
+
+	f.scopes = f.scopes[:len(f.scopes)-1]
+	f.depth--
+}
+
+// Record assignment to variable with name
+func (f *captureFinder) assign(name string) {
+	for i := len(f.scopes) - 1; i >= 0; i-- {
+		if decl, ok := f.scopes[i].vars[name]; ok {
+			if decl != nil && f.scopes[i].depth < f.depth {
+				f.captured[decl] = true
+			}
+			return
+		}
+	}
+
+	if f.depth > 0 {
+		f.outside[name] = true
+	}
+}
+
+// Visit node and its children, skipping nil expressions
+func (f *captureFinder) visit(node ast.Node) {
+	switch n := node.(type) {
+	case nil:
+	case *ast.VarDeclaration:
+		f.visit(n.Value)
+		f.scopes[len(f.scopes)-1].vars[n.Name] = n
+	case *ast.AssignmentStmt:
+		f.assign(n.Name)
+		f.visit(n.Value)
+	case *ast.FunDeclaration:
+		f.function(n.Params, n.Body.Stmts)
+	case *ast.LambdaExpr:
+		f.function(n.Params, n.Body.Stmts)
+	case *ast.ClassDeclaration:
+		for _, method := range n.Methods {
+			f.visit(method)
+		}
+	case *ast.BlockStmt:
+		f.block(n.Stmts)
+	case *ast.BlockExpr:
+		f.block(n.Stmts)
+	case *ast.ExprStmt:
+		f.visit(n.Expr)
+	case *ast.IndexAssignmentStmt:
+		f.visit(n.Object)
+		f.visit(n.Index)
+		f.visit(n.Value)
+	case *ast.FieldAssignmentStmt:
+		f.visit(n.Object)
+		f.visit(n.Value)
+	case *ast.IfStmt:
+		f.visit(n.Condition)
+		f.visit(n.Then)
+		if n.Else != nil {
+			f.visit(n.Else)
+		}
+	case *ast.WhileStmt:
+		f.visit(n.Condition)
+		f.visit(n.Block)
+	case *ast.ForStmt:
+		f.visit(n.Iterable)
+		f.visit(n.Block)
+	case *ast.ReturnStmt:
+		f.visit(n.Value)
+	case *ast.ThrowStmt:
+		f.visit(n.Value)
+	case *ast.BinaryExpr:
+		f.visit(n.Left)
+		f.visit(n.Right)
+	case *ast.LogicalExpr:
+		f.visit(n.Left)
+		f.visit(n.Right)
+	case *ast.GroupingExpr:
+		f.visit(n.Expr)
+	case *ast.UnaryExpr:
+		f.visit(n.Expr)
+	case *ast.IfExpr:
+		f.visit(n.Condition)
+		f.visit(n.Then)
+		if n.Else != nil {
+			f.visit(n.Else)
+		}
+	case *ast.TryExpr:
+		f.visit(n.Body)
+		for _, catch := range n.Catches {
+			f.visit(catch.Body)
+		}
+		if n.Finally != nil {
+			f.visit(n.Finally)
+		}
+	case *ast.CallExpr:
+		f.visit(n.Callee)
+		for _, arg := range n.Args {
+			f.visit(arg)
+		}
+	case *ast.RangeExpr:
+		f.visit(n.Start)
+		f.visit(n.End)
+		f.visit(n.Step)
+	case *ast.MatchExpr:
+		f.visit(n.Subject)
+		for _, arm := range n.Arms {
+			f.visit(arm.Guard)
+			f.visit(arm.Body)
+		}
+	case *ast.ListLiteral:
+		for _, e := range n.Elements {
+			f.visit(e)
+		}
+	case *ast.MapLiteral:
+		for i := range n.Keys {
+			f.visit(n.Keys[i])
+			f.visit(n.Values[i])
+		}
+	case *ast.IndexExpr:
+		f.visit(n.Object)
+		f.visit(n.Index)
+	case *ast.GetExpr:
+		f.visit(n.Object)
+	case *ast.ElvisExpr:
+		f.visit(n.Left)
+		f.visit(n.Right)
+	case *ast.NonNullExpr:
+		f.visit(n.Expr)
+	case *ast.IsExpr:
+		f.visit(n.Expr)
+	case *ast.CastExpr:
+		f.visit(n.Expr)
+	case *ast.UnwrapExpr:
+		f.visit(n.Expr)
+	}
+}
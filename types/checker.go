@@ -1,458 +1,2894 @@
 package types
 
 import (
-	"errors"
 	"fmt"
 	"interpreter/ast"
+	"interpreter/diagnostic"
 	"interpreter/token"
-	"strconv"
+	"maps"
+	"slices"
+	"strings"
 )
 
 type Checker struct {
-	file    string
-	Errors  []error
-	context *context
+	file       string
+	Errors     []error
+	Types      map[ast.Expr]Type // Type of every checked expression, complete after Visit
+	context    *context
+	function   *Function      // Signature of function currently being checked
+	lambda     bool           // Whether a lambda body is currently being checked
+	loops      []*loop        // Enclosing loops, innermost last
+	inferences []*inference   // Unknown types introduced while checking
+	unassigned []*variable    // Variables declared without a value in the current function or lambda
+	captured   *captureFinder // Variables assigned in lambdas and functions, which are never narrowed
 }
 
 func NewChecker(file string) *Checker {
 	return &Checker{
-		file:    file,
-		Errors:  []error{},
-		context: newContext(),
+		file:     file,
+		Errors:   []error{},
+		Types:    map[ast.Expr]Type{},
+		context:  newContext(),
+		captured: findCaptured(nil),
 	}
 }
 
 func (c *Checker) Visit(program []ast.Stmt) bool {
+	// Variables of earlier programs, e.g. lines of the REPL, may be assigned by functions of this one
+	c.captured = findCaptured(program)
+	for name := range c.captured.outside {
+		if v, ok := c.context.lookup(name).(*variable); ok {
+			v.declared().captured = true
+		}
+	}
+
 	c.collectTopLevelSymbols(program)
 
 	for _, s := range program {
 		c.checkStmt(s)
 	}
+
+	c.solve()
 	return len(c.Errors) == 0
 }
 
 // Collect all top level symbols (functions, types)
 // and save in symbol table
 func (c *Checker) collectTopLevelSymbols(statements []ast.Stmt) {
-	for range statements {
+	// Classes and enums are declared before their members are resolved,
+	// so fields and methods can refer to any top level type
+	classes := []*class{}
+	enums := []*enum{}
+	interfaces := []*iface{}
+	declared := map[string]bool{}
+	for _, s := range statements {
+		switch decl := s.(type) {
+		case *ast.InterfaceDeclaration:
+			if declared[decl.Name] || getPrimitives()[decl.Name] != nil {
+				c.error(fmt.Sprintf("Redefinition of type %s", decl.Name), decl)
+				continue
+			}
+			declared[decl.Name] = true
+
+			interfaces = append(interfaces, c.declareInterface(decl))
+		case *ast.ClassDeclaration:
+			if declared[decl.Name] || getPrimitives()[decl.Name] != nil {
+				c.error(fmt.Sprintf("Redefinition of type %s", decl.Name), decl)
+				continue
+			}
+			declared[decl.Name] = true
+
+			classes = append(classes, c.declareClass(decl))
+		case *ast.EnumDeclaration:
+			if declared[decl.Name] || getPrimitives()[decl.Name] != nil {
+				c.error(fmt.Sprintf("Redefinition of type %s", decl.Name), decl)
+				continue
+			}
+			declared[decl.Name] = true
+
+			enums = append(enums, c.declareEnum(decl))
+		}
 	}
-}
 
-// Typecheck statement
-func (c *Checker) checkStmt(stmt ast.Stmt) bool {
-	switch n := stmt.(type) {
-	case *ast.BlockStmt:
-		return c.checkBlockStmt(n)
-	case *ast.ExprStmt:
-		return c.checkExpr(n.Expr) != nil
-	case *ast.VarDeclaration:
-		return c.checkVarDeclaration(n)
-	case *ast.AssignmentStmt:
-		return c.checkAssignment(n)
-	case *ast.IfStmt:
-		return c.checkIfStmt(n)
-	case *ast.WhileStmt:
-		return c.checkWhileStmt(n)
-	default:
-		panic(fmt.Sprintf("unexpected ast.Stmt: %#v", n))
+	// Interfaces are resolved first, so classes can be checked against them
+	for _, i := range interfaces {
+		c.resolveInterface(i)
 	}
-}
 
-// Typecheck while statement
-func (c *Checker) checkWhileStmt(stmt *ast.WhileStmt) bool {
-	cond := c.checkExpr(stmt.Condition)
-	if cond != NewBoolean() {
-		c.error("Expected boolean condition", stmt)
-		return false
+	for _, cls := range classes {
+		c.resolveClass(cls)
 	}
 
-	return c.checkBlockStmt(stmt.Block)
-}
+	for _, e := range enums {
+		c.resolveEnum(e)
+	}
 
-// Typecheck if statements
-func (c *Checker) checkIfStmt(stmt *ast.IfStmt) bool {
-	cond := c.checkExpr(stmt.Condition)
-	if cond != NewBoolean() {
-		c.error("Expected boolean condition", stmt)
-		return false
+	declared = map[string]bool{}
+	for _, s := range statements {
+		if decl, ok := s.(*ast.FunDeclaration); ok {
+			if declared[decl.Name] {
+				c.error(fmt.Sprintf("Redefinition of function %s", decl.Name), decl)
+				continue
+			}
+			declared[decl.Name] = true
+
+			c.declareFunction(decl)
+		}
 	}
+}
 
-	then := c.checkBlockStmt(stmt.Then)
-	if stmt.Else != nil {
-		otherwise := c.checkBlockStmt(stmt.Else)
-		return then && otherwise
+// Resolve signature of function declaration and
+// define it in the current context
+func (c *Checker) declareFunction(decl *ast.FunDeclaration) *function {
+	f := &function{
+		name: decl.Name,
+		kind: c.resolveSignature(decl),
+		decl: decl,
 	}
 
-	return then
+	c.context.define(decl.Name, f)
+	return f
 }
 
-// Typecheck blocks
-func (c *Checker) checkBlockStmt(stmt *ast.BlockStmt) bool {
+// Resolve parameter and return types of function or method
+// Type parameters of generic functions are only visible in the signature and body
+func (c *Checker) resolveSignature(decl *ast.FunDeclaration) *Function {
+	type_params := c.declareTypeParams(decl.TypeParams)
+
 	c.enterBlock()
 	defer c.exitBlock()
+	c.defineTypeParams(type_params)
 
-	ok := true
-	for _, s := range stmt.Stmts {
-		if !c.checkStmt(s) {
-			ok = false
-		}
+	params := make([]Type, len(decl.Params))
+	for i, param := range decl.Params {
+		params[i] = c.resolveType(param.Type)
 	}
 
-	return ok
+	var ret Type = NewUnit()
+	if decl.ReturnType != nil {
+		ret = c.resolveType(decl.ReturnType)
+	}
+
+	f := NewFunction(params, ret)
+	f.TypeParams = type_params
+	return f
 }
 
-// Typecheck variable declarations
-func (c *Checker) checkVarDeclaration(stmt *ast.VarDeclaration) bool {
-	var t Type
-	// Variables needs either a type, an initial value or both
-	if stmt.Type == nil {
-		// Infer basic type
-		t = c.checkExpr(stmt.Value)
-		if t == nil {
-			return false
-		}
-	} else {
-		// Lookup type in symbol table
-		declared_type, ok := c.context.types[stmt.Type.Value]
-		if !ok {
-			c.error(fmt.Sprintf("Undefined type: %s", stmt.Type.Value), stmt)
-			return false
+// Create type variables for type parameters of declaration
+func (c *Checker) declareTypeParams(params []*ast.TypeParam) []*TypeVar {
+	vars := []*TypeVar{}
+	for _, param := range params {
+		if slices.ContainsFunc(vars, func(v *TypeVar) bool { return v.name == param.Name }) {
+			c.error(fmt.Sprintf("Duplicate type parameter %s", param.Name), param)
+			continue
 		}
 
-		// If both type and value is given, verify that they match
-		if stmt.Value != nil {
-			inferred := c.checkExpr(stmt.Value)
-			if inferred != declared_type {
-				c.error(fmt.Sprintf("Inferred type does not match declared type"), stmt)
-			}
-		}
+		vars = append(vars, NewTypeVar(param.Name))
+	}
 
-		t = declared_type
+	return vars
+}
+
+// Define type parameters as types in the current context
+func (c *Checker) defineTypeParams(vars []*TypeVar) {
+	for _, v := range vars {
+		c.context.types[v.name] = v
 	}
+}
 
-	v, err := newVariable(stmt, t, c.context.symbols)
-	if err != nil {
-		c.Errors = append(c.Errors, err)
-		return false
+// Define class type and constructor in the current context
+// Members are resolved separately with resolveClass
+func (c *Checker) declareClass(decl *ast.ClassDeclaration) *class {
+	cls := &class{
+		name: decl.Name,
+		kind: NewClass(decl.Name),
+		decl: decl,
 	}
+	cls.kind.Data = decl.Data
+	cls.kind.SetTypeParams(c.declareTypeParams(decl.TypeParams))
 
-	c.context.define(stmt.Name, v)
-	return true
+	c.context.types[decl.Name] = cls.kind
+	c.context.define(decl.Name, cls)
+	return cls
 }
 
-// Typecheck assignments
-func (c *Checker) checkAssignment(stmt *ast.AssignmentStmt) bool {
-	sym := c.context.lookup(stmt.Name)
-	if sym == nil {
-		c.error(fmt.Sprintf("Undefined identifier: %s", stmt.Name), stmt)
-		return false
+// Resolve types of fields and signatures of methods of class
+func (c *Checker) resolveClass(cls *class) {
+	c.enterBlock()
+	defer c.exitBlock()
+	c.defineTypeParams(cls.kind.TypeParams)
+
+	for _, expr := range cls.decl.Interfaces {
+		t := c.context.lookupType(expr.Name)
+		if t == nil {
+			c.error(fmt.Sprintf("Undefined type: %s", expr.Name), expr)
+			continue
+		}
+
+		i, ok := t.(*Interface)
+		if !ok {
+			c.error(fmt.Sprintf("%s is not an interface", expr.Name), expr)
+			continue
+		}
+
+		if slices.Contains(cls.kind.Interfaces(), i) {
+			c.error(fmt.Sprintf("Duplicate interface %s in class %s", expr.Name, cls.name), expr)
+			continue
+		}
+
+		cls.kind.AddInterface(i)
 	}
 
-	t := c.checkExpr(stmt.Value)
-	if t == nil {
-		return false
+	members := map[string]bool{}
+	for _, field := range cls.decl.Fields {
+		if members[field.Name] {
+			c.errorAt(fmt.Sprintf("Duplicate member %s in class %s", field.Name, cls.name), field.Pos)
+			continue
+		}
+		members[field.Name] = true
+
+		cls.kind.AddField(&Field{
+			Name:    field.Name,
+			Type:    c.resolveType(field.Type),
+			Mutable: field.DeclType == token.VAR,
+		})
 	}
 
-	// Check correct type
-	if sym.Type() != t {
-		c.error(fmt.Sprintf("Cannot assign %s to variable of type %s", t.Name(), sym.Type().Name()), stmt)
-		return false
+	// Data classes provide copy
+	if cls.decl.Data {
+		members["copy"] = true
 	}
 
-	switch v := sym.(type) {
-	case *function:
-	case *variable:
-		// Disallow assignment if variable is not mutable
-		// unless variable is not initialized
-		if !v.mutable && v.initialized {
-			c.error(fmt.Sprintf("Cannot assign to immutable variable %s", stmt.Name), stmt)
-			return false
+	for _, method := range cls.decl.Methods {
+		if members[method.Name] {
+			c.error(fmt.Sprintf("Duplicate member %s in class %s", method.Name, cls.name), method)
+			continue
 		}
-		v.initialized = true
-	default:
-		panic(fmt.Sprintf("unexpected types.symbol: %#v", v))
-	}
+		members[method.Name] = true
 
-	return true
+		cls.kind.AddMethod(method.Name, c.resolveSignature(method))
+	}
 }
 
-// Typecheck expressions
-func (c *Checker) checkExpr(expr ast.Expr) Type {
-	switch n := expr.(type) {
-	case *ast.BinaryExpr:
-		return c.checkBinaryExpr(n)
-	case *ast.GroupingExpr:
-		return c.checkExpr(n.Expr)
-	case *ast.Ident:
-		return c.checkIdent(n)
-	case *ast.LiteralExpr:
-		return c.checkLiteralExpr(n)
-	case *ast.UnaryExpr:
-		return c.checkUnaryExpr(n)
-	case *ast.BlockExpr:
-		return c.checkBlockExpr(n)
-	case *ast.IfExpr:
-		return c.checkIfExpr(n)
-	case *ast.LogicalExpr:
-		return c.checkLogicalExpr(n)
-	default:
-		panic(fmt.Sprintf("unexpected ast.Expr: %#v", n))
+// Define interface type in the current context
+// Methods are resolved separately with resolveInterface
+func (c *Checker) declareInterface(decl *ast.InterfaceDeclaration) *iface {
+	i := &iface{
+		name: decl.Name,
+		kind: NewInterface(decl.Name),
+		decl: decl,
 	}
+
+	c.context.types[decl.Name] = i.kind
+	c.context.define(decl.Name, i)
+	return i
 }
 
-// Typecheck logical expression
-func (c *Checker) checkLogicalExpr(expr *ast.LogicalExpr) Type {
-	left := c.checkExpr(expr.Left)
-	if left != NewBoolean() {
-		c.error("Expected boolean left operand", expr)
-		return nil
-	}
+// Resolve signatures of methods required by interface
+func (c *Checker) resolveInterface(i *iface) {
+	for _, method := range i.decl.Methods {
+		if _, ok := i.kind.Methods[method.Name]; ok {
+			c.error(fmt.Sprintf("Duplicate method %s in interface %s", method.Name, i.name), method)
+			continue
+		}
 
-	right := c.checkExpr(expr.Right)
-	if right != NewBoolean() {
-		c.error("Expected boolean right operand", expr)
-		return nil
+		i.kind.Methods[method.Name] = c.resolveSignature(method)
 	}
-
-	return NewBoolean()
 }
 
-// Typecheck if expression
-func (c *Checker) checkIfExpr(expr *ast.IfExpr) Type {
-	cond := c.checkExpr(expr.Condition)
-	if cond != NewBoolean() {
-		c.error("Expected boolean condition", expr)
-		return nil
+// Define enum type in the current context
+// Variants are resolved separately with resolveEnum
+func (c *Checker) declareEnum(decl *ast.EnumDeclaration) *enum {
+	e := &enum{
+		name: decl.Name,
+		kind: NewEnum(decl.Name),
+		decl: decl,
 	}
 
-	then := c.checkBlockExpr(expr.Then)
-	otherwise := c.checkBlockExpr(expr.Else)
+	c.context.types[decl.Name] = e.kind
+	c.context.define(decl.Name, e)
+	return e
+}
 
-	if then != otherwise {
-		c.error("Both branches must return the same type", expr)
-		return nil
+// Resolve types of values carried by variants of enum
+func (c *Checker) resolveEnum(e *enum) {
+	if len(e.decl.Variants) == 0 {
+		c.error(fmt.Sprintf("Enum %s must have at least one variant", e.name), e.decl)
 	}
 
-	return then
-}
+	for _, decl := range e.decl.Variants {
+		if e.kind.Variant(decl.Name) != nil {
+			c.error(fmt.Sprintf("Duplicate variant %s in enum %s", decl.Name, e.name), decl)
+			continue
+		}
 
-// Typecheck block expressions
-func (c *Checker) checkBlockExpr(expr *ast.BlockExpr) Type {
-	c.enterBlock()
-	defer c.exitBlock()
+		variant := &Variant{
+			Name:   decl.Name,
+			Fields: make([]*Field, len(decl.Fields)),
+		}
 
-	var t Type
-	for i, n := range expr.Stmts {
-		stmt := n.(ast.Stmt)
-		switch s := stmt.(type) {
-		case *ast.AssignmentStmt:
-			c.checkAssignment(s)
-		case *ast.BlockStmt:
-			c.checkBlockStmt(s)
-		case *ast.ExprStmt:
-			if i == len(expr.Stmts)-1 {
-				t = c.checkExpr(s.Expr)
-			} else {
-				c.checkExpr(s.Expr)
+		for i, field := range decl.Fields {
+			variant.Fields[i] = &Field{
+				Name:    field.Name,
+				Type:    c.resolveType(field.Type),
+				Mutable: false,
 			}
-		case *ast.VarDeclaration:
-			c.checkVarDeclaration(s)
-		default:
-			panic(fmt.Sprintf("unexpected ast.Stmt: %#v", s))
 		}
-	}
 
-	return t
+		e.kind.Variants = append(e.kind.Variants, variant)
+	}
 }
 
-// Typecheck identifiers
-func (c *Checker) checkIdent(expr *ast.Ident) Type {
-	sym := c.context.lookup(expr.Name)
-	if sym == nil {
-		c.error(fmt.Sprintf("Undefined identifier: %s", expr.Name), expr)
-		return nil
-	}
+// Resolve type expression using types in symbol table
+// Reports error and returns nil if not found
+func (c *Checker) resolveType(expr ast.TypeExpr) Type {
+	switch t := expr.(type) {
+	case *ast.NamedType:
+		if t.Name == "List" {
+			if len(t.Args) != 1 {
+				c.error(fmt.Sprintf("Expected 1 type argument for List, found %d", len(t.Args)), t)
+				return nil
+			}
 
-	switch v := sym.(type) {
-	case *function:
-	case *variable:
-		if !v.initialized {
-			c.error(fmt.Sprintf("Identifier used before intialized: %s", v.name), expr)
-			return nil
+			elem := c.resolveType(t.Args[0])
+			if elem == nil {
+				return nil
+			}
+
+			return NewList(elem)
 		}
-	default:
-		panic(fmt.Sprintf("unexpected types.symbol: %#v", v))
-	}
 
-	return sym.Type()
-}
+		if t.Name == "Map" {
+			if len(t.Args) != 2 {
+				c.error(fmt.Sprintf("Expected 2 type arguments for Map, found %d", len(t.Args)), t)
+				return nil
+			}
 
-// Typecheck literal expressions
-func (c *Checker) checkLiteralExpr(expr *ast.LiteralExpr) Type {
-	switch expr.Kind {
-	case token.CHAR:
-		return NewChar()
-	case token.REAL:
-		_, err := strconv.ParseFloat(expr.Value, 64)
-		if err != nil {
-			return nil
+			return c.resolveMapType(t, t.Args[0], t.Args[1])
 		}
-		return NewReal()
-	case token.STRING:
-		return NewString()
-	case token.INTEGER:
-		_, err := strconv.ParseInt(expr.Value, 10, 32)
-		if err != nil {
+
+		if t.Name == "Result" {
+			if len(t.Args) != 2 {
+				c.error(fmt.Sprintf("Expected 2 type arguments for Result, found %d", len(t.Args)), t)
+				return nil
+			}
+
+			value, err := c.resolveType(t.Args[0]), c.resolveType(t.Args[1])
+			if value == nil || err == nil {
+				return nil
+			}
+
+			return NewResult(value, err)
+		}
+
+		resolved := c.context.lookupType(t.Name)
+		if resolved == nil {
+			c.error(fmt.Sprintf("Undefined type: %s", t.Name), t)
 			return nil
 		}
-		return NewInteger()
-	case token.TRUE, token.FALSE:
-		return NewBoolean()
-	default:
-		panic(fmt.Sprintf("Unexpected token.TokenType: %#v", expr.Kind))
-	}
-}
 
-// Typecheck unary expressions
-func (c *Checker) checkUnaryExpr(expr *ast.UnaryExpr) Type {
-	right := c.checkExpr(expr.Expr)
-	if right == nil {
-		return nil
-	}
+		if cls, ok := resolved.(*Class); ok && len(cls.TypeParams) != 0 {
+			return c.instantiateClass(t, cls)
+		}
 
-	p, ok := right.(*Primitive)
-	if !ok {
-		// TODO: add type error
-		return nil
-	}
+		if len(t.Args) != 0 {
+			c.error(fmt.Sprintf("Type %s does not take type arguments", t.Name), t)
+			return nil
+		}
 
-	switch expr.Op.Kind {
-	case token.BANG:
-		switch p.kind {
-		case Boolean:
-			return p
-		default:
-			c.operatorError(expr)
+		return resolved
+	case *ast.FunctionType:
+		params := make([]Type, len(t.Params))
+		for i, param := range t.Params {
+			params[i] = c.resolveType(param)
+			if params[i] == nil {
+				return nil
+			}
+		}
+
+		ret := c.resolveType(t.Return)
+		if ret == nil {
 			return nil
 		}
 
-	case token.MINUS:
-		switch p.kind {
-		case Int, Real:
-			return p
-		default:
-			c.operatorError(expr)
+		return NewFunction(params, ret)
+	case *ast.ListType:
+		elem := c.resolveType(t.Elem)
+		if elem == nil {
 			return nil
 		}
 
-	case token.TILDE:
-		switch p.kind {
-		case Int:
-			return p
-		default:
-			c.operatorError(expr)
+		return NewList(elem)
+	case *ast.MapType:
+		return c.resolveMapType(t, t.Key, t.Value)
+	case *ast.NullableType:
+		elem := c.resolveType(t.Elem)
+		if elem == nil {
 			return nil
 		}
 
+		return NewNullable(elem)
 	default:
-		panic(fmt.Sprintf("unexpected token.TokenType: %#v", expr.Op.Kind))
+		panic(fmt.Sprintf("unexpected ast.TypeExpr: %#v", t))
 	}
 }
 
-// Typecheck binary expressions
-func (c *Checker) checkBinaryExpr(expr *ast.BinaryExpr) Type {
-	left := c.checkExpr(expr.Left)
-	right := c.checkExpr(expr.Right)
+// Resolve type arguments of generic class and instantiate it
+func (c *Checker) instantiateClass(expr *ast.NamedType, cls *Class) Type {
+	if len(expr.Args) != len(cls.TypeParams) {
+		c.error(fmt.Sprintf("Expected %d type arguments for %s, found %d", len(cls.TypeParams), expr.Name, len(expr.Args)), expr)
+		return nil
+	}
+
+	args := make([]Type, len(expr.Args))
+	for i, arg := range expr.Args {
+		args[i] = c.resolveType(arg)
+		if args[i] == nil {
+			return nil
+		}
+	}
+
+	return cls.Instantiate(args)
+}
+
+// Resolve map type from key and value types
+// Keys must be hashable primitives
+func (c *Checker) resolveMapType(expr ast.TypeExpr, key ast.TypeExpr, value ast.TypeExpr) Type {
+	k := c.resolveType(key)
+	v := c.resolveType(value)
+	if k == nil || v == nil {
+		return nil
+	}
+
+	if !isHashable(k) {
+		c.error(fmt.Sprintf("Cannot use %s as map key", k.Name()), expr)
+		return nil
+	}
+
+	return NewMap(k, v)
+}
+
+// Typecheck statement
+func (c *Checker) checkStmt(stmt ast.Stmt) bool {
+	switch n := stmt.(type) {
+	case *ast.BlockStmt:
+		return c.checkBlockStmt(n)
+	case *ast.ExprStmt:
+		return c.checkExpr(n.Expr) != nil
+	case *ast.VarDeclaration:
+		return c.checkVarDeclaration(n)
+	case *ast.AssignmentStmt:
+		return c.checkAssignment(n)
+	case *ast.IndexAssignmentStmt:
+		return c.checkIndexAssignment(n)
+	case *ast.IfStmt:
+		return c.checkIfStmt(n)
+	case *ast.WhileStmt:
+		return c.checkWhileStmt(n)
+	case *ast.ForStmt:
+		return c.checkForStmt(n)
+	case *ast.BreakStmt:
+		return c.checkJump("break", n.Label, n)
+	case *ast.ContinueStmt:
+		return c.checkJump("continue", n.Label, n)
+	case *ast.FunDeclaration:
+		return c.checkFunDeclaration(n)
+	case *ast.ReturnStmt:
+		return c.checkReturnStmt(n)
+	case *ast.ThrowStmt:
+		return c.checkThrowStmt(n)
+	case *ast.ClassDeclaration:
+		return c.checkClassDeclaration(n)
+	case *ast.EnumDeclaration:
+		return c.checkEnumDeclaration(n)
+	case *ast.InterfaceDeclaration:
+		return c.checkInterfaceDeclaration(n)
+	case *ast.FieldAssignmentStmt:
+		return c.checkFieldAssignment(n)
+	default:
+		panic(fmt.Sprintf("unexpected ast.Stmt: %#v", n))
+	}
+}
+
+// Typecheck function declaration
+func (c *Checker) checkFunDeclaration(stmt *ast.FunDeclaration) bool {
+	// Top level functions are already declared
+	f, ok := c.context.symbols[stmt.Name].(*function)
+	if !ok || f.decl != stmt {
+		f = c.declareFunction(stmt)
+	}
+
+	return c.checkFunctionBody(stmt, f.kind)
+}
+
+// Typecheck class declaration
+// Methods are checked with 'this' bound to an instance of the class
+func (c *Checker) checkClassDeclaration(stmt *ast.ClassDeclaration) bool {
+	// Top level classes are already declared
+	cls, ok := c.context.symbols[stmt.Name].(*class)
+	if !ok || cls.decl != stmt {
+		if getPrimitives()[stmt.Name] != nil {
+			c.error(fmt.Sprintf("Redefinition of type %s", stmt.Name), stmt)
+			return false
+		}
+
+		cls = c.declareClass(stmt)
+		c.resolveClass(cls)
+	}
+
+	for _, field := range cls.kind.Fields() {
+		if field.Type == nil {
+			return false
+		}
+	}
+
+	ok = true
+	for _, method := range stmt.Methods {
+		c.enterBlock()
+		c.defineTypeParams(cls.kind.TypeParams)
+		c.context.define("this", &variable{
+			name:        "this",
+			kind:        cls.kind,
+			mutable:     false,
+			initialized: true,
+		})
+
+		if !c.checkFunctionBody(method, cls.kind.Method(method.Name)) {
+			ok = false
+		}
+		c.exitBlock()
+	}
+
+	for _, i := range cls.kind.Interfaces() {
+		if !c.checkConformance(stmt, cls.kind, i) {
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Check that class has every method required by interface with the same signature
+func (c *Checker) checkConformance(stmt *ast.ClassDeclaration, cls *Class, i *Interface) bool {
+	names := slices.Sorted(maps.Keys(i.Methods))
+
+	ok := true
+	for _, name := range names {
+		required := i.Methods[name]
+		method := cls.Method(name)
+		if method == nil {
+			c.error(fmt.Sprintf("Class %s does not implement method %s of interface %s", cls.Name(), name, i.Name()), stmt)
+			ok = false
+			continue
+		}
+
+		// Signatures that failed to resolve are already reported
+		if !resolved(required) || !resolved(method) {
+			continue
+		}
+
+		if !Identical(method, required) {
+			decl := stmt.Methods[slices.IndexFunc(stmt.Methods, func(m *ast.FunDeclaration) bool { return m.Name == name })]
+			c.error(fmt.Sprintf("Method %s of class %s has type %s, but interface %s requires %s", name, cls.Name(), method.Name(), i.Name(), required.Name()), decl)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Check if parameter and return types of signature were resolved
+func resolved(f *Function) bool {
+	return f.Return != nil && !slices.Contains(f.Params, nil)
+}
+
+// Typecheck interface declaration
+func (c *Checker) checkInterfaceDeclaration(stmt *ast.InterfaceDeclaration) bool {
+	// Top level interfaces are already declared
+	i, ok := c.context.symbols[stmt.Name].(*iface)
+	if !ok || i.decl != stmt {
+		if getPrimitives()[stmt.Name] != nil {
+			c.error(fmt.Sprintf("Redefinition of type %s", stmt.Name), stmt)
+			return false
+		}
+
+		i = c.declareInterface(stmt)
+		c.resolveInterface(i)
+	}
+
+	for _, method := range i.kind.Methods {
+		if !resolved(method) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Typecheck enum declaration
+func (c *Checker) checkEnumDeclaration(stmt *ast.EnumDeclaration) bool {
+	// Top level enums are already declared
+	e, ok := c.context.symbols[stmt.Name].(*enum)
+	if !ok || e.decl != stmt {
+		if getPrimitives()[stmt.Name] != nil {
+			c.error(fmt.Sprintf("Redefinition of type %s", stmt.Name), stmt)
+			return false
+		}
+
+		e = c.declareEnum(stmt)
+		c.resolveEnum(e)
+	}
+
+	for _, variant := range e.kind.Variants {
+		for _, field := range variant.Fields {
+			if field.Type == nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Typecheck body of function or method with signature
+func (c *Checker) checkFunctionBody(stmt *ast.FunDeclaration, signature *Function) bool {
+	if signature == nil {
+		return false
+	}
+
+	for _, param := range signature.Params {
+		if param == nil {
+			return false
+		}
+	}
+
+	if signature.Return == nil {
+		return false
+	}
+
+	// Local functions may run at any later point, after any assignment in their body
+	c.invalidateAll(assigned(stmt.Body))
+
+	// Loops do not extend into function bodies,
+	// and assignments in the body do not count outside of it
+	enclosing, lambda, loops := c.function, c.lambda, c.loops
+	saved, unassigned := c.saveAssignments(), c.unassigned
+	c.function, c.lambda, c.loops, c.unassigned = signature, false, nil, nil
+	c.enterBlock()
+	defer func() {
+		c.exitBlock()
+		c.function, c.lambda, c.loops, c.unassigned = enclosing, lambda, loops, unassigned
+		c.restoreAssignments(saved)
+	}()
+	c.forgetMutableNarrowings()
+
+	c.defineTypeParams(signature.TypeParams)
+
+	for i, param := range stmt.Params {
+		c.context.define(param.Name, &variable{
+			name:        param.Name,
+			kind:        signature.Params[i],
+			mutable:     false,
+			initialized: true,
+		})
+	}
+
+	if !c.checkBlockStmt(stmt.Body) {
+		return false
+	}
+
+	if !Identical(signature.Return, NewUnit()) && !alwaysReturns(stmt.Body.Stmts) {
+		c.error(fmt.Sprintf("Missing return in function %s", stmt.Name), stmt)
+		return false
+	}
+
+	return true
+}
+
+// Typecheck return statement
+func (c *Checker) checkReturnStmt(stmt *ast.ReturnStmt) bool {
+	// The value of a lambda is the last expression in its body
+	if c.lambda {
+		c.error("Return not allowed in lambda", stmt)
+		return false
+	}
+
+	if c.function == nil {
+		c.error("Return outside function", stmt)
+		return false
+	}
+
+	var t Type = NewUnit()
+	if stmt.Value != nil {
+		t = c.checkExprExpecting(stmt.Value, c.function.Return)
+		if t == nil {
+			return false
+		}
+	}
+
+	if !c.assignable(t, c.function.Return) {
+		c.error(fmt.Sprintf("Cannot return %s from function returning %s", t.Name(), c.function.Return.Name()), stmt)
+		return false
+	}
+
+	return true
+}
+
+// Typecheck throw statement
+// Thrown values must implement Error
+func (c *Checker) checkThrowStmt(stmt *ast.ThrowStmt) bool {
+	t := c.checkExprExpecting(stmt.Value, NewError())
+	if t == nil {
+		return false
+	}
+
+	if !c.assignable(t, NewError()) {
+		c.error(fmt.Sprintf("Cannot throw %s, which does not implement Error", t.Name()), stmt.Value)
+		return false
+	}
+
+	return true
+}
+
+// Typecheck while statement
+func (c *Checker) checkWhileStmt(stmt *ast.WhileStmt) bool {
+	// Later iterations see assignments of earlier ones
+	c.invalidateAll(assigned(stmt))
+
+	cond := c.checkExpr(stmt.Condition)
+	if !c.unify(cond, NewBoolean()) {
+		c.error("Expected boolean condition", stmt)
+		return false
+	}
+
+	// Body may run any number of times, so its assignments are not definite after the loop
+	saved, names := c.saveAssignments(), iterationAssigned(stmt.Block, stmt.Label)
+	c.assignedByLoop(names)
+	loop := c.enterLoop(stmt.Label)
+	c.enterNarrowed(c.narrowings(stmt.Condition, true))
+	ok := c.checkBlockStmt(stmt.Block)
+	c.exitBlock()
+	c.exitLoop()
+
+	// Loop ends when the condition is false, which 'while true' never is, or by a break
+	c.restoreAssignments(saved)
+	c.assignedByLoop(names)
+	ended := c.saveAssignments()
+	if literal, is_literal := stmt.Condition.(*ast.LiteralExpr); is_literal && literal.Kind == token.TRUE {
+		ended = nil
+	}
+	c.restoreAfterLoop(mergeAssignments(ended, loop.breaks))
+
+	return ok
+}
+
+// Typecheck break and continue statements
+// Must be inside a loop, or a loop with the given label
+func (c *Checker) checkJump(keyword string, label string, stmt ast.Stmt) bool {
+	if len(c.loops) == 0 {
+		c.error(fmt.Sprintf("'%s' outside loop", keyword), stmt)
+		return false
+	}
+
+	// Innermost loop with the label is the target
+	var target *loop
+	for i := len(c.loops) - 1; i >= 0 && target == nil; i-- {
+		if label == "" || c.loops[i].label == label {
+			target = c.loops[i]
+		}
+	}
+
+	if target == nil {
+		c.error(fmt.Sprintf("Undefined loop label: %s", label), stmt)
+		return false
+	}
+
+	// Variables assigned before a break are assigned after the loop on that path
+	if keyword == "break" {
+		target.breaks = mergeAssignments(target.breaks, c.saveAssignments())
+	}
+
+	return true
+}
+
+// Typecheck for statement
+// Type of loop variable is inferred from the iterable
+func (c *Checker) checkForStmt(stmt *ast.ForStmt) bool {
+	// Later iterations see assignments of earlier ones
+	c.invalidateAll(assigned(stmt.Block))
+
+	iterable := c.checkExpr(stmt.Iterable)
+	if iterable == nil {
+		return false
+	}
+
+	// Maps iterate over keys, optionally together with values
+	var elem, value Type
+	switch t := iterable.(type) {
+	case *Range:
+		elem = NewInteger()
+	case *List:
+		elem = t.Elem
+	case *Map:
+		elem, value = t.Key, t.Value
+	case *Primitive:
+		if t.kind == String {
+			elem = NewChar()
+		}
+	}
+
+	if elem == nil {
+		c.error(fmt.Sprintf("Cannot iterate over %s", iterable.Name()), stmt.Iterable)
+		return false
+	}
+
+	if stmt.Value != "" && value == nil {
+		c.error(fmt.Sprintf("Cannot iterate over entries of %s", iterable.Name()), stmt.Iterable)
+		return false
+	}
+
+	// Body may run any number of times, so its assignments are not definite after the loop
+	saved, names := c.saveAssignments(), iterationAssigned(stmt.Block, stmt.Label)
+	c.assignedByLoop(names)
+	c.enterBlock()
+	loop := c.enterLoop(stmt.Label)
+	defer func() {
+		c.exitLoop()
+		c.exitBlock()
+
+		// Loop ends when the iterable is exhausted or by a break
+		c.restoreAssignments(saved)
+		c.assignedByLoop(names)
+		c.restoreAfterLoop(mergeAssignments(c.saveAssignments(), loop.breaks))
+	}()
+
+	c.context.define(stmt.Name, &variable{
+		name:        stmt.Name,
+		kind:        elem,
+		mutable:     false,
+		initialized: true,
+	})
+
+	if stmt.Value != "" {
+		c.context.define(stmt.Value, &variable{
+			name:        stmt.Value,
+			kind:        value,
+			mutable:     false,
+			initialized: true,
+		})
+	}
+
+	return c.checkBlockStmt(stmt.Block)
+}
+
+// Typecheck if statements
+func (c *Checker) checkIfStmt(stmt *ast.IfStmt) bool {
+	cond := c.checkExpr(stmt.Condition)
+	if !c.unify(cond, NewBoolean()) {
+		c.error("Expected boolean condition", stmt)
+		return false
+	}
+
+	then_jumps := alwaysJumps(stmt.Then.Stmts)
+	else_jumps := stmt.Else != nil && alwaysJumps(stmt.Else.Stmts)
+
+	// Variables are assigned after the statement if every branch that completes assigns them
+	before := c.saveAssignments()
+	c.enterNarrowed(c.narrowings(stmt.Condition, true))
+	then := c.checkBlockStmt(stmt.Then)
+	c.exitBlock()
+
+	after_then := c.saveAssignments()
+	if then_jumps {
+		after_then = nil
+	}
+	c.restoreAssignments(before)
+
+	otherwise := true
+	if stmt.Else != nil {
+		c.enterNarrowed(c.narrowings(stmt.Condition, false))
+		otherwise = c.checkBlockStmt(stmt.Else)
+		c.exitBlock()
+	}
+
+	after_else := c.saveAssignments()
+	if else_jumps {
+		after_else = nil
+	}
+	c.restoreAssignments(mergeAssignments(after_then, after_else))
+
+	// Code after the statement is only reached through a branch that completes,
+	// so what its condition says holds for the rest of the block
+	if then_jumps && !else_jumps {
+		c.narrowAfter(c.narrowings(stmt.Condition, false), stmt.Else)
+	} else if else_jumps && !then_jumps {
+		c.narrowAfter(c.narrowings(stmt.Condition, true), stmt.Then)
+	}
+
+	return then && otherwise
+}
+
+// Typecheck blocks
+func (c *Checker) checkBlockStmt(stmt *ast.BlockStmt) bool {
+	c.enterBlock()
+	defer c.exitBlock()
+
+	ok := true
+	for _, s := range stmt.Stmts {
+		if !c.checkStmt(s) {
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Typecheck variable declarations
+func (c *Checker) checkVarDeclaration(stmt *ast.VarDeclaration) bool {
+	var t Type
+	// Variables needs either a type, an initial value or both
+	if stmt.Type == nil {
+		// Infer basic type
+		t = c.checkExpr(stmt.Value)
+		if t == nil {
+			// Variable without a type is still defined, so its uses are not reported as undefined
+			c.context.define(stmt.Name, &variable{name: stmt.Name, mutable: stmt.DeclType == token.VAR, initialized: true, assigned: true})
+			return false
+		}
+
+		if _, ok := t.(*Null); ok {
+			c.error(fmt.Sprintf("Cannot infer type of null, declare %s with a nullable type", stmt.Name), stmt)
+			return false
+		}
+	} else {
+		// Lookup type in symbol table
+		declared_type := c.resolveType(stmt.Type)
+		if declared_type == nil {
+			return false
+		}
+
+		// If both type and value is given, verify that they match
+		if stmt.Value != nil {
+			inferred := c.checkExprExpecting(stmt.Value, declared_type)
+			if inferred != nil && !c.assignable(inferred, declared_type) {
+				c.error(fmt.Sprintf("Inferred type does not match declared type"), stmt)
+			}
+		}
+
+		t = declared_type
+	}
+
+	// Variables whose declaration failed can be redefined
+	if cur, ok := c.context.symbols[stmt.Name]; ok && cur.Type() != nil && !Identical(t, cur.Type()) {
+		c.error(fmt.Sprintf("Redefinition of %s with different type", stmt.Name), stmt)
+		return false
+	}
+
+	v := newVariable(stmt, t)
+	v.captured = c.captured.captured[stmt]
+
+	if stmt.Value == nil {
+		c.track(v)
+	}
+
+	c.context.define(stmt.Name, v)
+	return true
+}
+
+// Typecheck assignments
+func (c *Checker) checkAssignment(stmt *ast.AssignmentStmt) bool {
+	sym := c.context.lookup(stmt.Name)
+	if sym == nil {
+		c.error(fmt.Sprintf("Undefined identifier: %s", stmt.Name), stmt)
+		return false
+	}
+
+	if _, ok := sym.(*function); ok {
+		c.error(fmt.Sprintf("Cannot assign to function %s", stmt.Name), stmt)
+		return false
+	}
+
+	if _, ok := sym.(*class); ok {
+		c.error(fmt.Sprintf("Cannot assign to class %s", stmt.Name), stmt)
+		return false
+	}
+
+	if _, ok := sym.(*enum); ok {
+		c.error(fmt.Sprintf("Cannot assign to enum %s", stmt.Name), stmt)
+		return false
+	}
+
+	if _, ok := sym.(*iface); ok {
+		c.error(fmt.Sprintf("Cannot assign to interface %s", stmt.Name), stmt)
+		return false
+	}
+
+	// Narrowed variables can be assigned any value of their declared type
+	declared := sym.Type()
+	if v, ok := sym.(*variable); ok {
+		declared = v.declared().kind
+	}
+
+	// Variables whose declaration failed are already reported
+	if declared == nil {
+		c.checkExpr(stmt.Value)
+		return false
+	}
+
+	t := c.checkExprExpecting(stmt.Value, declared)
+	if t == nil {
+		return false
+	}
+
+	// Compound assignment applies the operator to the current value
+	if op, ok := token.CompoundOperator(stmt.Op.Kind); ok {
+		if v, ok := sym.(*variable); ok && !v.initialized {
+			c.error(fmt.Sprintf("Identifier used before intialized: %s", v.name), stmt)
+			return false
+		}
+
+		result := binaryType(op, prune(sym.Type()), t)
+		if result == nil {
+			c.error(fmt.Sprintf("Invalid operation: %s %s %v (%s)", stmt.Name, stmt.Op.Value, stmt.Value, operatorMismatch(stmt.Op.Value, prune(sym.Type()), t)), stmt)
+			return false
+		}
+		t = result
+	}
+
+	// Check correct type
+	if !c.assignable(t, declared) {
+		c.error(fmt.Sprintf("Cannot assign %s to variable of type %s", t.Name(), declared.Name()), stmt)
+		return false
+	}
+
+	switch v := sym.(type) {
+	case *function:
+	case *variable:
+		// Disallow assignment if variable is not mutable
+		// unless variable is not assigned on any path
+		declared := v.declared()
+		if !v.mutable {
+			if declared.initialized {
+				c.error(fmt.Sprintf("Cannot assign to immutable variable %s", stmt.Name), stmt)
+				return false
+			}
+
+			// Assigned from here on either way, to not report uses after this
+			if declared.assigned {
+				c.error(fmt.Sprintf("Immutable variable %s may already be assigned", stmt.Name), stmt)
+				declared.initialized = true
+				return false
+			}
+
+			// Closures may run any number of times
+			if !slices.Contains(c.unassigned, declared) {
+				c.error(fmt.Sprintf("Cannot assign immutable variable %s from closure", stmt.Name), stmt)
+				return false
+			}
+		}
+		declared.initialized, declared.assigned = true, true
+		c.invalidate(stmt.Name)
+	default:
+		panic(fmt.Sprintf("unexpected types.symbol: %#v", v))
+	}
+
+	return true
+}
+
+// Typecheck assignment to list element or map entry
+// The list or map must be reachable through a mutable variable
+func (c *Checker) checkIndexAssignment(stmt *ast.IndexAssignmentStmt) bool {
+	object := c.checkExpr(stmt.Object)
+	if object == nil {
+		return false
+	}
+
+	var index, elem Type
+	switch t := object.(type) {
+	case *List:
+		index, elem = NewInteger(), t.Elem
+	case *Map:
+		index, elem = t.Key, t.Value
+	default:
+		c.error(fmt.Sprintf("Cannot assign to element of %s", object.Name()), stmt)
+		return false
+	}
+
+	if !c.checkIndex(stmt.Index, index) {
+		return false
+	}
+
+	if ident, ok := rootIdent(stmt.Object); ok {
+		if v, ok := c.context.lookup(ident.Name).(*variable); ok && !v.mutable {
+			c.error(fmt.Sprintf("Cannot assign to element of immutable variable %s", ident.Name), stmt)
+			return false
+		}
+	}
+
+	t := c.checkExprExpecting(stmt.Value, elem)
+	if t == nil {
+		return false
+	}
+
+	// Compound assignment applies the operator to the current element
+	if op, ok := token.CompoundOperator(stmt.Op.Kind); ok {
+		result := binaryType(op, elem, t)
+		if result == nil {
+			c.error(fmt.Sprintf("Invalid operation: element %s %v (%s)", stmt.Op.Value, stmt.Value, operatorMismatch(stmt.Op.Value, elem, t)), stmt)
+			return false
+		}
+		t = result
+	}
+
+	if !c.assignable(t, elem) {
+		c.error(fmt.Sprintf("Cannot assign %s to element of type %s", t.Name(), elem.Name()), stmt)
+		return false
+	}
+
+	return true
+}
+
+// Typecheck assignment to field of class instance
+// Only fields declared with 'var' can be assigned
+func (c *Checker) checkFieldAssignment(stmt *ast.FieldAssignmentStmt) bool {
+	object := c.checkExpr(stmt.Object)
+	if object == nil {
+		return false
+	}
+
+	cls, ok := object.(*Class)
+	if !ok {
+		c.error(fmt.Sprintf("Cannot assign to member %s of type %s", stmt.Name, object.Name()), stmt)
+		return false
+	}
+
+	field := cls.Field(stmt.Name)
+	if field == nil {
+		if cls.Method(stmt.Name) != nil {
+			c.error(fmt.Sprintf("Cannot assign to method %s", stmt.Name), stmt)
+		} else {
+			c.error(fmt.Sprintf("Undefined member %s of type %s", stmt.Name, cls.Name()), stmt)
+		}
+		return false
+	}
+
+	t := c.checkExprExpecting(stmt.Value, field.Type)
+	if t == nil {
+		return false
+	}
+
+	// Compound assignment applies the operator to the current value
+	if op, ok := token.CompoundOperator(stmt.Op.Kind); ok {
+		result := binaryType(op, field.Type, t)
+		if result == nil {
+			c.error(fmt.Sprintf("Invalid operation: %s %s %v (%s)", stmt.Name, stmt.Op.Value, stmt.Value, operatorMismatch(stmt.Op.Value, field.Type, t)), stmt)
+			return false
+		}
+		t = result
+	}
+
+	if !c.assignable(t, field.Type) {
+		c.error(fmt.Sprintf("Cannot assign %s to field of type %s", t.Name(), field.Type.Name()), stmt)
+		return false
+	}
+
+	if !field.Mutable {
+		c.error(fmt.Sprintf("Cannot assign to immutable field %s", stmt.Name), stmt)
+		return false
+	}
+
+	return true
+}
+
+// Find the variable an indexed expression is rooted in, e.g. 'xs' in xs[0][1]
+func rootIdent(expr ast.Expr) (*ast.Ident, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e, true
+	case *ast.IndexExpr:
+		return rootIdent(e.Object)
+	case *ast.GroupingExpr:
+		return rootIdent(e.Expr)
+	default:
+		return nil, false
+	}
+}
+
+// Typecheck expressions
+// The type of every expression is recorded for later phases
+func (c *Checker) checkExpr(expr ast.Expr) Type {
+	return c.record(expr, c.checkExprKind(expr))
+}
+
+// Save type of expression
+// Returns the type with solved unknowns followed
+func (c *Checker) record(expr ast.Expr, t Type) Type {
+	if t != nil {
+		c.Types[expr] = t
+	}
+
+	return prune(t)
+}
+
+// Typecheck expression depending on its kind
+func (c *Checker) checkExprKind(expr ast.Expr) Type {
+	switch n := expr.(type) {
+	case *ast.BinaryExpr:
+		return c.checkBinaryExpr(n)
+	case *ast.GroupingExpr:
+		return c.checkExpr(n.Expr)
+	case *ast.Ident:
+		return c.checkIdent(n)
+	case *ast.LiteralExpr:
+		return c.checkLiteralExpr(n)
+	case *ast.UnaryExpr:
+		return c.checkUnaryExpr(n, nil)
+	case *ast.BlockExpr:
+		return c.checkBlockExpr(n)
+	case *ast.IfExpr:
+		return c.checkIfExpr(n, nil)
+	case *ast.LogicalExpr:
+		return c.checkLogicalExpr(n)
+	case *ast.CallExpr:
+		return c.checkCallExpr(n, nil)
+	case *ast.LambdaExpr:
+		return c.checkLambdaExpr(n, nil)
+	case *ast.RangeExpr:
+		return c.checkRangeExpr(n)
+	case *ast.MatchExpr:
+		return c.checkMatchExpr(n)
+	case *ast.ListLiteral:
+		return c.checkListLiteral(n, nil)
+	case *ast.MapLiteral:
+		return c.checkMapLiteral(n, nil)
+	case *ast.IndexExpr:
+		return c.checkIndexExpr(n)
+	case *ast.GetExpr:
+		return c.checkGetExpr(n)
+	case *ast.ThisExpr:
+		return c.checkThisExpr(n)
+	case *ast.ElvisExpr:
+		return c.checkElvisExpr(n)
+	case *ast.NonNullExpr:
+		return c.checkNonNullExpr(n)
+	case *ast.IsExpr:
+		return c.checkIsExpr(n)
+	case *ast.CastExpr:
+		return c.checkCastExpr(n)
+	case *ast.TryExpr:
+		return c.checkTryExpr(n)
+	case *ast.UnwrapExpr:
+		return c.checkUnwrapExpr(n)
+	default:
+		panic(fmt.Sprintf("unexpected ast.Expr: %#v", n))
+	}
+}
+
+// Typecheck expression where the type is known from context
+// Lets empty list and map literals, and lambda parameters, take their type from the context
+func (c *Checker) checkExprExpecting(expr ast.Expr, expected Type) Type {
+	// Branches of if expressions can have different types held by the expected one, e.g. an interface
+	if e, ok := expr.(*ast.IfExpr); ok && expected != nil && solved(expected) {
+		return c.record(e, c.checkIfExpr(e, prune(expected)))
+	}
+
+	// Type arguments of generic calls can be inferred from the expected type, e.g. T as int? for Box<int?>
+	if e, ok := expr.(*ast.CallExpr); ok && expected != nil && solved(expected) {
+		return c.record(e, c.checkCallExpr(e, prune(expected)))
+	}
+
+	expected = NonNull(prune(expected))
+
+	switch e := expr.(type) {
+	case *ast.ListLiteral:
+		if t, ok := expected.(*List); ok {
+			return c.record(e, c.checkListLiteral(e, t))
+		}
+	case *ast.MapLiteral:
+		if t, ok := expected.(*Map); ok {
+			return c.record(e, c.checkMapLiteral(e, t))
+		}
+	case *ast.LambdaExpr:
+		if t, ok := expected.(*Function); ok {
+			return c.record(e, c.checkLambdaExpr(e, t))
+		}
+	case *ast.LiteralExpr:
+		if t, ok := expected.(*Primitive); ok && t.kind.isNumeric() && token.IsNumber(e.Kind) {
+			return c.record(e, c.checkNumberLiteral(e, false, t))
+		}
+	case *ast.UnaryExpr:
+		if t, ok := expected.(*Primitive); ok && t.kind.isNumeric() {
+			return c.record(e, c.checkUnaryExpr(e, t))
+		}
+	}
+
+	return c.checkExpr(expr)
+}
+
+// Typecheck list literal
+// The element type must hold all elements
+// The element type of an empty list without context is inferred from later use
+func (c *Checker) checkListLiteral(expr *ast.ListLiteral, expected *List) Type {
+	if len(expr.Elements) == 0 {
+		if expected == nil {
+			return NewList(c.fresh(expr, "element type of empty list"))
+		}
+
+		return expected
+	}
+
+	var elem Type
+	if expected != nil {
+		elem = expected.Elem
+	}
+
+	// Without context the element type holds all elements, e.g. int? for [1, null]
+	ok := true
+	for _, element := range expr.Elements {
+		t := c.checkExprExpecting(element, elem)
+		if t == nil {
+			ok = false
+			continue
+		}
+
+		if elem == nil {
+			elem = t
+			continue
+		}
+
+		joined := elem
+		if expected == nil {
+			joined = c.join(elem, t)
+		} else if !c.assignable(t, elem) {
+			joined = nil
+		}
+
+		if joined == nil {
+			c.error(fmt.Sprintf("Cannot use %s as element in list of %s", t.Name(), elem.Name()), element)
+			ok = false
+			continue
+		}
+		elem = joined
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return NewList(elem)
+}
+
+// Typecheck map literal
+// All keys must have the same hashable type, and the value type must hold all values
+// Key and value types of an empty map without context are inferred from later use
+func (c *Checker) checkMapLiteral(expr *ast.MapLiteral, expected *Map) Type {
+	if len(expr.Keys) == 0 {
+		if expected == nil {
+			return NewMap(c.fresh(expr, "key type of empty map"), c.fresh(expr, "value type of empty map"))
+		}
+
+		return expected
+	}
+
+	var key, value Type
+	if expected != nil {
+		key, value = expected.Key, expected.Value
+	}
+
+	ok := true
+	for n := range expr.Keys {
+		k := c.checkExprExpecting(expr.Keys[n], key)
+		v := c.checkExprExpecting(expr.Values[n], value)
+		if k == nil || v == nil {
+			ok = false
+			continue
+		}
+
+		if key == nil {
+			// Unknown key types are checked once they are inferred
+			if _, unknown := k.(*Meta); !unknown && !isHashable(k) {
+				c.error(fmt.Sprintf("Cannot use %s as map key", k.Name()), expr.Keys[n])
+				return nil
+			}
+			key, value = k, v
+			continue
+		}
+
+		if !c.unify(k, key) {
+			c.error(fmt.Sprintf("Cannot use %s as key in map of %s", k.Name(), NewMap(key, value).Name()), expr.Keys[n])
+			ok = false
+		}
+
+		joined := value
+		if expected == nil {
+			joined = c.join(value, v)
+		} else if !c.assignable(v, value) {
+			joined = nil
+		}
+
+		if joined == nil {
+			c.error(fmt.Sprintf("Cannot use %s as value in map of %s", v.Name(), NewMap(key, value).Name()), expr.Values[n])
+			ok = false
+			continue
+		}
+		value = joined
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return NewMap(key, value)
+}
+
+// Typecheck indexing
+// Lists are indexed by integers, maps by keys, and strings give their characters
+func (c *Checker) checkIndexExpr(expr *ast.IndexExpr) Type {
+	object := c.checkExpr(expr.Object)
+	if object == nil {
+		return nil
+	}
+
+	switch t := object.(type) {
+	case *List:
+		if c.checkIndex(expr.Index, NewInteger()) {
+			return t.Elem
+		}
+		return nil
+	case *Map:
+		if c.checkIndex(expr.Index, t.Key) {
+			return t.Value
+		}
+		return nil
+	case *Primitive:
+		if t.kind == String {
+			if c.checkIndex(expr.Index, NewInteger()) {
+				return NewChar()
+			}
+			return nil
+		}
+	case *Meta:
+		c.error("Cannot infer type of indexed value, add a type annotation", expr)
+		return nil
+	}
+
+	c.error(fmt.Sprintf("Cannot index %s", object.Name()), expr)
+	return nil
+}
+
+// Typecheck index against the expected index type
+func (c *Checker) checkIndex(index ast.Expr, expected Type) bool {
+	t := c.checkExprExpecting(index, expected)
+	if t == nil {
+		return false
+	}
+
+	if !c.unify(t, expected) {
+		c.error(fmt.Sprintf("Index must be %s, found %s", expected.Name(), t.Name()), index)
+		return false
+	}
+
+	return true
+}
+
+// Typecheck member access
+func (c *Checker) checkGetExpr(expr *ast.GetExpr) Type {
+	// Variants are accessed through the name of the enum
+	if ident, ok := expr.Object.(*ast.Ident); ok {
+		if e, ok := c.context.lookup(ident.Name).(*enum); ok {
+			return c.checkVariantAccess(expr, e.kind)
+		}
+	}
+
+	object := c.checkExpr(expr.Object)
+	if object == nil {
+		return nil
+	}
+
+	// Members of nullable values can only be accessed with '?.'
+	if n, ok := object.(*Nullable); ok {
+		if !expr.Safe {
+			c.error(fmt.Sprintf("Cannot access member %s of nullable type %s, use '?.'", expr.Name, object.Name()), expr)
+			return nil
+		}
+
+		member := c.checkMember(expr, n.Elem)
+		if member == nil {
+			return nil
+		}
+
+		return NewNullable(member)
+	}
+
+	return c.checkMember(expr, object)
+}
+
+// Typecheck access of member of non-null value
+func (c *Checker) checkMember(expr *ast.GetExpr, object Type) Type {
+	switch t := prune(object).(type) {
+	case *Meta:
+		c.error(fmt.Sprintf("Cannot infer type of value to access member %s, add a type annotation", expr.Name), expr)
+		return nil
+	case *Class:
+		if field := t.Field(expr.Name); field != nil {
+			return field.Type
+		}
+
+		if method := t.Method(expr.Name); method != nil {
+			return method
+		}
+
+		if t.Data && expr.Name == "copy" {
+			return t.Constructor()
+		}
+	case *Interface:
+		if method, ok := t.Methods[expr.Name]; ok {
+			return method
+		}
+	case *List:
+		if expr.Name == "length" {
+			return NewInteger()
+		}
+	case *Result:
+		switch expr.Name {
+		case "isOk", "isErr":
+			return NewFunction([]Type{}, NewBoolean())
+		case "unwrapOr":
+			return NewFunction([]Type{t.Value}, t.Value)
+		}
+	case *Map:
+		switch expr.Name {
+		case "length":
+			return NewInteger()
+		case "contains":
+			return NewFunction([]Type{t.Key}, NewBoolean())
+		case "remove":
+			if ident, ok := rootIdent(expr.Object); ok {
+				if v, ok := c.context.lookup(ident.Name).(*variable); ok && !v.mutable {
+					c.error(fmt.Sprintf("Cannot remove from immutable variable %s", ident.Name), expr)
+					return nil
+				}
+			}
+			return NewFunction([]Type{t.Key}, NewUnit())
+		}
+	case *Primitive:
+		if t.kind == String && expr.Name == "length" {
+			return NewInteger()
+		}
+
+		// Numbers are converted between types explicitly, e.g. 'x.toU8()'
+		if target := conversion(expr.Name); t.kind.isNumeric() && target != nil {
+			return NewFunction([]Type{}, target)
+		}
+
+		if t.kind != Unit && expr.Name == "toString" {
+			return NewFunction([]Type{}, NewString())
+		}
+	}
+
+	c.error(fmt.Sprintf("Undefined member %s of type %s", expr.Name, object.Name()), expr)
+	return nil
+}
+
+// Typecheck access of enum variant
+// Variants without values are values of the enum, other variants construct one
+func (c *Checker) checkVariantAccess(expr *ast.GetExpr, e *Enum) Type {
+	variant := e.Variant(expr.Name)
+	if variant == nil {
+		c.error(fmt.Sprintf("Undefined variant %s of enum %s", expr.Name, e.Name()), expr)
+		return nil
+	}
+
+	if len(variant.Fields) == 0 {
+		return e
+	}
+
+	params := make([]Type, len(variant.Fields))
+	for i, field := range variant.Fields {
+		params[i] = field.Type
+	}
+
+	return NewFunction(params, e)
+}
+
+// Typecheck 'this', which is bound in methods
+func (c *Checker) checkThisExpr(expr *ast.ThisExpr) Type {
+	sym := c.context.lookup("this")
+	if sym == nil {
+		c.error("Cannot use 'this' outside of class", expr)
+		return nil
+	}
+
+	return sym.Type()
+}
+
+// Typecheck range expression
+// Bounds and step must be int, ranges of other integer types are not supported
+func (c *Checker) checkRangeExpr(expr *ast.RangeExpr) Type {
+	operands := []ast.Expr{expr.Start, expr.End}
+	if expr.Step != nil {
+		operands = append(operands, expr.Step)
+	}
+
+	ok := true
+	for _, operand := range operands {
+		t := c.checkExprExpecting(operand, NewInteger())
+		if t == nil {
+			ok = false
+		} else if !c.unify(t, NewInteger()) {
+			err := c.error(fmt.Sprintf("Expected int in range, found %s", t.Name()), operand)
+			if p, is_primitive := t.(*Primitive); is_primitive && p.kind.isInteger() {
+				err.WithNote(fmt.Sprintf("ranges only hold int, convert %s bounds with 'toInt()'", t.Name()))
+			}
+			ok = false
+		}
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return NewRange()
+}
+
+// Typecheck match expression
+// All arms must have the same type and cover every value of the subject
+func (c *Checker) checkMatchExpr(expr *ast.MatchExpr) Type {
+	subject := c.checkExpr(expr.Subject)
+	if subject == nil {
+		return nil
+	}
+
+	// Variables are assigned after the match if every arm that completes assigns them
+	before := c.saveAssignments()
+	var after assignments
+
+	var t Type
+	ok := true
+	for i, arm := range expr.Arms {
+		if !c.checkMatchArm(arm, subject) {
+			ok = false
+			continue
+		}
+
+		if arm.Fall {
+			if i == len(expr.Arms)-1 {
+				c.error("Cannot fall through from last arm", arm)
+				ok = false
+			} else if hasBindings(expr.Arms[i+1].Pattern) {
+				c.error("Cannot fall into arm with bindings", expr.Arms[i+1])
+				ok = false
+			}
+			continue
+		}
+
+		c.restoreAssignments(before)
+		c.enterBlock()
+		c.bindPattern(arm.Pattern, subject)
+		body := c.checkExpr(arm.Body)
+		c.exitBlock()
+
+		if block, ok := arm.Body.(*ast.BlockExpr); !ok || !alwaysJumps(block.Stmts) {
+			after = mergeAssignments(after, c.saveAssignments())
+		}
+
+		if body == nil {
+			ok = false
+			continue
+		}
+
+		if t == nil {
+			t = body
+		} else if joined := c.join(t, body); joined != nil {
+			t = joined
+		} else {
+			c.error(fmt.Sprintf("All arms must have the same type, found %s and %s", t.Name(), body.Name()), arm.Body)
+			ok = false
+		}
+	}
+
+	if after != nil {
+		c.restoreAssignments(after)
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if !c.isExhaustive(expr.Arms, subject) {
+		if variants, ok := variantNames(subject); ok {
+			missing := missingVariants(expr.Arms, variants)
+			c.error(fmt.Sprintf("Non-exhaustive match over %s, missing %s", subject.Name(), strings.Join(missing, ", ")), expr)
+			return nil
+		}
+
+		c.error(fmt.Sprintf("Non-exhaustive match over %s", subject.Name()), expr)
+		return nil
+	}
+
+	return t
+}
+
+// Typecheck pattern and guard of match arm against type of subject
+func (c *Checker) checkMatchArm(arm *ast.MatchArm, subject Type) bool {
+	if !c.checkPattern(arm.Pattern, subject) {
+		return false
+	}
+
+	if arm.Guard == nil {
+		return true
+	}
+
+	c.enterBlock()
+	defer c.exitBlock()
+
+	c.bindPattern(arm.Pattern, subject)
+	guard := c.checkExpr(arm.Guard)
+	if guard == nil {
+		return false
+	}
+
+	if !c.unify(guard, NewBoolean()) {
+		c.error("Expected boolean guard", arm.Guard)
+		return false
+	}
+
+	return true
+}
+
+// Typecheck pattern against type of subject
+func (c *Checker) checkPattern(pattern ast.Pattern, subject Type) bool {
+	switch p := pattern.(type) {
+	case *ast.WildcardPattern, *ast.BindingPattern:
+		return true
+	case *ast.LiteralPattern:
+		t := c.checkExprExpecting(p.Value, subject)
+		if t == nil {
+			return false
+		}
+
+		if !c.unify(t, subject) {
+			c.error(fmt.Sprintf("Cannot match %s against %s", t.Name(), subject.Name()), p)
+			return false
+		}
+
+		return true
+	case *ast.RangePattern:
+		start := c.checkExprExpecting(p.Start, subject)
+		end := c.checkExprExpecting(p.End, subject)
+		if start == nil || end == nil {
+			return false
+		}
+
+		if n, ok := start.(*Primitive); !ok || !Identical(start, end) || !n.kind.isInteger() && n.kind != Char {
+			c.error("Range pattern must have integer or char bounds", p)
+			return false
+		}
+
+		if !c.unify(start, subject) {
+			c.error(fmt.Sprintf("Cannot match %s against %s", start.Name(), subject.Name()), p)
+			return false
+		}
+
+		return true
+	case *ast.VariantPattern:
+		if p.Enum == "" {
+			return c.checkResultPattern(p, subject)
+		}
+
+		e, ok := c.context.lookupType(p.Enum).(*Enum)
+		if !ok {
+			c.error(fmt.Sprintf("Undefined enum: %s", p.Enum), p)
+			return false
+		}
+
+		if !c.unify(e, subject) {
+			c.error(fmt.Sprintf("Cannot match %s against %s", e.Name(), subject.Name()), p)
+			return false
+		}
+
+		variant := e.Variant(p.Variant)
+		if variant == nil {
+			c.error(fmt.Sprintf("Undefined variant %s of enum %s", p.Variant, e.Name()), p)
+			return false
+		}
+
+		if len(p.Fields) != len(variant.Fields) {
+			c.error(fmt.Sprintf("Expected %d fields for variant %s, found %d", len(variant.Fields), variant.Name, len(p.Fields)), p)
+			return false
+		}
+
+		ok = true
+		for i, field := range p.Fields {
+			if !c.checkPattern(field, variant.Fields[i].Type) {
+				ok = false
+			}
+		}
+
+		return ok
+	default:
+		panic(fmt.Sprintf("unexpected ast.Pattern: %#v", p))
+	}
+}
+
+// Typecheck pattern matching variant of result, e.g. Ok(v)
+func (c *Checker) checkResultPattern(p *ast.VariantPattern, subject Type) bool {
+	r, ok := prune(subject).(*Result)
+	if !ok {
+		c.error(fmt.Sprintf("Cannot match %s against %s", p.Variant, subject.Name()), p)
+		return false
+	}
+
+	field := resultField(r, p.Variant)
+	if field == nil {
+		c.error(fmt.Sprintf("Undefined variant %s of Result", p.Variant), p)
+		return false
+	}
+
+	if len(p.Fields) != 1 {
+		c.error(fmt.Sprintf("Expected 1 field for variant %s, found %d", p.Variant, len(p.Fields)), p)
+		return false
+	}
+
+	return c.checkPattern(p.Fields[0], field)
+}
+
+// Get type of value carried by variant of result, or nil if result has no such variant
+func resultField(r *Result, variant string) Type {
+	switch variant {
+	case "Ok":
+		return r.Value
+	case "Err":
+		return r.Error
+	default:
+		return nil
+	}
+}
+
+// Define variables bound by pattern in current context
+func (c *Checker) bindPattern(pattern ast.Pattern, subject Type) {
+	switch p := pattern.(type) {
+	case *ast.BindingPattern:
+		c.context.define(p.Name, &variable{
+			name:        p.Name,
+			kind:        subject,
+			mutable:     false,
+			initialized: true,
+		})
+	case *ast.VariantPattern:
+		if r, ok := prune(subject).(*Result); ok {
+			c.bindPattern(p.Fields[0], resultField(r, p.Variant))
+			return
+		}
+
+		variant := subject.(*Enum).Variant(p.Variant)
+		for i, field := range p.Fields {
+			c.bindPattern(field, variant.Fields[i].Type)
+		}
+	}
+}
+
+// Check if pattern binds any variables
+func hasBindings(pattern ast.Pattern) bool {
+	switch p := pattern.(type) {
+	case *ast.BindingPattern:
+		return true
+	case *ast.VariantPattern:
+		for _, field := range p.Fields {
+			if hasBindings(field) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Check if pattern matches every value
+func irrefutable(pattern ast.Pattern) bool {
+	switch pattern.(type) {
+	case *ast.WildcardPattern, *ast.BindingPattern:
+		return true
+	default:
+		return false
+	}
+}
+
+// Get names of variants not covered by any arm
+// A variant is covered by an unguarded arm whose fields all match every value
+func missingVariants(arms []*ast.MatchArm, variants []string) []string {
+	covered := map[string]bool{}
+	for _, arm := range arms {
+		if arm.Guard != nil {
+			continue
+		}
+
+		p, ok := arm.Pattern.(*ast.VariantPattern)
+		if !ok {
+			continue
+		}
+
+		if !slices.ContainsFunc(p.Fields, func(field ast.Pattern) bool { return !irrefutable(field) }) {
+			covered[p.Variant] = true
+		}
+	}
+
+	missing := []string{}
+	for _, v := range variants {
+		if !covered[v] {
+			missing = append(missing, v)
+		}
+	}
+
+	return missing
+}
+
+// Get names of variants of enum or result type
+// Returns false for types without variants
+func variantNames(t Type) ([]string, bool) {
+	switch x := t.(type) {
+	case *Enum:
+		names := make([]string, len(x.Variants))
+		for i, v := range x.Variants {
+			names[i] = v.Name
+		}
+		return names, true
+	case *Result:
+		return []string{"Ok", "Err"}, true
+	default:
+		return nil, false
+	}
+}
+
+// Check if arms of match cover every value of subject
+// Only arms without guards are taken into account
+func (c *Checker) isExhaustive(arms []*ast.MatchArm, subject Type) bool {
+	covered := map[string]bool{}
+	for _, arm := range arms {
+		if arm.Guard != nil {
+			continue
+		}
+
+		switch p := arm.Pattern.(type) {
+		case *ast.WildcardPattern, *ast.BindingPattern:
+			return true
+		case *ast.LiteralPattern:
+			covered[p.Value.Value] = true
+		}
+	}
+
+	if Identical(subject, NewBoolean()) {
+		return covered["true"] && covered["false"]
+	}
+
+	if variants, ok := variantNames(subject); ok {
+		return len(missingVariants(arms, variants)) == 0
+	}
+
+	return false
+}
+
+// Typecheck lambda expression
+// Return type is inferred from the body
+// Parameters without type take it from the expected function type, or from later use
+func (c *Checker) checkLambdaExpr(expr *ast.LambdaExpr, expected *Function) Type {
+	if expected != nil && len(expected.Params) != len(expr.Params) {
+		expected = nil
+	}
+
+	params := make([]Type, len(expr.Params))
+	for i, param := range expr.Params {
+		switch {
+		case param.Type != nil:
+			params[i] = c.resolveType(param.Type)
+		case expected != nil && expected.Params[i] != nil:
+			params[i] = expected.Params[i]
+		default:
+			params[i] = c.fresh(param, fmt.Sprintf("type of parameter %s", param.Name))
+		}
+
+		if params[i] == nil {
+			return nil
+		}
+	}
+
+	// Lambda may run at any later point, after any assignment in its body
+	c.invalidateAll(assigned(expr.Body))
+
+	// Assignments in the lambda do not count outside of it, as it may never run
+	enclosing, lambda, loops := c.function, c.lambda, c.loops
+	saved, unassigned := c.saveAssignments(), c.unassigned
+	c.function, c.lambda, c.loops, c.unassigned = nil, true, nil, nil
+	c.enterBlock()
+	defer func() {
+		c.exitBlock()
+		c.function, c.lambda, c.loops, c.unassigned = enclosing, lambda, loops, unassigned
+		c.restoreAssignments(saved)
+	}()
+	c.forgetMutableNarrowings()
+
+	for i, param := range expr.Params {
+		c.context.define(param.Name, &variable{
+			name:        param.Name,
+			kind:        params[i],
+			mutable:     false,
+			initialized: true,
+		})
+	}
+
+	ret := c.checkBlockExpr(expr.Body)
+	if ret == nil {
+		return nil
+	}
+
+	return NewFunction(params, ret)
+}
+
+// Typecheck function calls
+func (c *Checker) checkCallExpr(expr *ast.CallExpr, expected Type) Type {
+	callee := c.checkExpr(expr.Callee)
+	if callee == nil {
+		return nil
+	}
+
+	// Methods accessed with '?.' are not called if the object is null
+	safe := false
+	if get, ok := expr.Callee.(*ast.GetExpr); ok && get.Safe {
+		if n, ok := callee.(*Nullable); ok {
+			callee, safe = n.Elem, true
+		}
+	}
+
+	// Values of unknown type that are called must be functions
+	if m, ok := callee.(*Meta); ok {
+		params := make([]Type, len(expr.Args))
+		for i, arg := range expr.Args {
+			params[i] = c.fresh(arg, "argument type")
+		}
+
+		callee = NewFunction(params, c.fresh(expr, "return type of call"))
+		c.unify(m, callee)
+	}
+
+	f, ok := callee.(*Function)
+	if !ok {
+		c.error(fmt.Sprintf("Cannot call non-function type %s", callee.Name()), expr)
+		return nil
+	}
+
+	if get, ok := expr.Callee.(*ast.GetExpr); ok && get.Name == "copy" {
+		if cls, ok := f.Return.(*Class); ok && cls.Data {
+			if !c.checkCopyArgs(expr, cls) {
+				return nil
+			}
+			return cls
+		}
+	}
+
+	for i, label := range expr.Labels {
+		if label != "" {
+			c.error("Named arguments are only supported by copy", expr.Args[i])
+			return nil
+		}
+	}
+
+	if len(expr.Args) != len(f.Params) {
+		c.error(fmt.Sprintf("Expected %d arguments, found %d", len(f.Params), len(expr.Args)), expr)
+		return nil
+	}
+
+	// Type arguments of generic functions are inferred from the arguments and later use
+	first := len(c.inferences)
+	f = c.instantiate(f, expr)
+	type_args := c.inferences[first:]
+
+	// Type arguments are solved from the expected type before the arguments are checked,
+	// unless the call gives a different type that is only assignable to it, e.g. a class for an interface
+	if expected != nil && len(type_args) > 0 {
+		if safe {
+			expected = NonNull(expected)
+		}
+
+		bindings := make([]Type, len(type_args))
+		for i, inf := range type_args {
+			bindings[i] = inf.meta.binding
+		}
+
+		if !c.unify(f.Return, expected) {
+			for i, inf := range type_args {
+				inf.meta.binding = bindings[i]
+			}
+		}
+	}
+
+	// Type arguments only inferred from arguments of exactly that type are widened to hold all of them,
+	// e.g. int? for pick(1, null)
+	widenable := map[*Meta]bool{}
+	for _, param := range f.Params {
+		if m, ok := param.(*Meta); ok {
+			widenable[m] = true
+		}
+	}
+	for _, param := range f.Params {
+		if _, ok := param.(*Meta); !ok {
+			for m := range widenable {
+				widenable[m] = widenable[m] && !occurs(m, param)
+			}
+		}
+	}
+
+	ok = true
+	for i, arg := range expr.Args {
+		t := c.checkExprExpecting(arg, f.Params[i])
+		if t == nil {
+			ok = false
+			continue
+		}
+
+		// Parameter types that failed to resolve are already reported
+		if f.Params[i] == nil || c.assignable(t, f.Params[i]) {
+			continue
+		}
+
+		if m, is_meta := f.Params[i].(*Meta); !is_meta || !widenable[m] || !c.widen(m, t) {
+			c.error(fmt.Sprintf("Cannot use %s as argument of type %s", t.Name(), f.Params[i].Name()), arg)
+			ok = false
+		}
+	}
+
+	// Type arguments that cannot be inferred because of errors in the arguments are not reported
+	if !ok {
+		for _, inf := range type_args {
+			inf.quiet = true
+		}
+		return nil
+	}
+
+	ret := f.Return
+	if safe {
+		return NewNullable(ret)
+	}
+
+	return ret
+}
+
+// Typecheck arguments of copy of data class
+// Positional arguments replace fields in order, named arguments replace the named field
+func (c *Checker) checkCopyArgs(expr *ast.CallExpr, cls *Class) bool {
+	given := map[string]bool{}
+	named := false
+
+	ok := true
+	for i, arg := range expr.Args {
+		var field *Field
+		if expr.Labels[i] == "" {
+			if named {
+				c.error("Positional argument after named argument", arg)
+				return false
+			}
+
+			fields := cls.Fields()
+			if i >= len(fields) {
+				c.error(fmt.Sprintf("Expected at most %d arguments, found %d", len(fields), len(expr.Args)), expr)
+				return false
+			}
+
+			field = fields[i]
+		} else {
+			named = true
+
+			field = cls.Field(expr.Labels[i])
+			if field == nil {
+				c.error(fmt.Sprintf("Undefined field %s of type %s", expr.Labels[i], cls.Name()), arg)
+				ok = false
+				continue
+			}
+		}
+
+		if given[field.Name] {
+			c.error(fmt.Sprintf("Field %s given more than once", field.Name), arg)
+			ok = false
+			continue
+		}
+		given[field.Name] = true
+
+		t := c.checkExprExpecting(arg, field.Type)
+		if t == nil {
+			ok = false
+			continue
+		}
+
+		if field.Type != nil && !c.assignable(t, field.Type) {
+			c.error(fmt.Sprintf("Cannot use %s as value of field %s of type %s", t.Name(), field.Name, field.Type.Name()), arg)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Typecheck logical expression
+func (c *Checker) checkLogicalExpr(expr *ast.LogicalExpr) Type {
+	left := c.checkExpr(expr.Left)
+	if !c.unify(left, NewBoolean()) {
+		c.error("Expected boolean left operand", expr)
+		return nil
+	}
+
+	// Right operand is only evaluated if left is true for '&&' and false for '||'
+	c.enterNarrowed(c.narrowings(expr.Left, expr.Op.Kind == token.LAND))
+	right := c.checkExpr(expr.Right)
+	c.exitBlock()
+
+	if !c.unify(right, NewBoolean()) {
+		c.error("Expected boolean right operand", expr)
+		return nil
+	}
+
+	return NewBoolean()
+}
+
+// Typecheck check of runtime type
+// The checked type must be narrower than the type of the value, and known at runtime
+func (c *Checker) checkIsExpr(expr *ast.IsExpr) Type {
+	value := c.checkExpr(expr.Expr)
+	target := c.resolveType(expr.Type)
+	if value == nil || target == nil {
+		return nil
+	}
+
+	if _, ok := value.(*Meta); ok {
+		c.error("Cannot infer type of value checked with 'is', add a type annotation", expr)
+		return nil
+	}
+
+	if !checkable(target) {
+		c.error(fmt.Sprintf("Cannot check for type %s at runtime", target.Name()), expr.Type)
+		return nil
+	}
+
+	// Values of type parameters can have any type
+	_, generic := NonNull(value).(*TypeVar)
+	if !generic && disjoint(value, target) {
+		c.error(fmt.Sprintf("Value of type %s is never %s", value.Name(), target.Name()), expr)
+		return nil
+	}
+
+	return NewBoolean()
+}
+
+// Typecheck cast to other type
+// Numbers are converted between numeric types, other values are checked at runtime like with 'is'
+// Safe casts ('as?') give null instead of failing
+func (c *Checker) checkCastExpr(expr *ast.CastExpr) Type {
+	value := c.checkExpr(expr.Expr)
+	target := c.resolveType(expr.Type)
+	if value == nil || target == nil {
+		return nil
+	}
+
+	if _, ok := value.(*Meta); ok {
+		c.error("Cannot infer type of value cast with 'as', add a type annotation", expr)
+		return nil
+	}
+
+	result := target
+	if expr.Safe {
+		result = NewNullable(target)
+	}
+
+	if isNumeric(value) && isNumeric(target) {
+		return result
+	}
+
+	// Casting to a supertype always succeeds
+	if Assignable(value, target) {
+		return result
+	}
+
+	if !checkable(target) {
+		c.error(fmt.Sprintf("Cannot cast to type %s at runtime", target.Name()), expr.Type)
+		return nil
+	}
+
+	// Values of type parameters can have any type
+	_, generic := NonNull(value).(*TypeVar)
+	if !generic && disjoint(value, target) {
+		c.error(fmt.Sprintf("Value of type %s can never be cast to %s", value.Name(), target.Name()), expr)
+		return nil
+	}
+
+	return result
+}
+
+// Typecheck if expression
+// Branches have the expected type if both are assignable to it, otherwise the type joining them
+func (c *Checker) checkIfExpr(expr *ast.IfExpr, expected Type) Type {
+	cond := c.checkExpr(expr.Condition)
+	if !c.unify(cond, NewBoolean()) {
+		c.error("Expected boolean condition", expr)
+		return nil
+	}
+
+	// Variables are assigned after the expression if every branch that completes assigns them
+	before := c.saveAssignments()
+	c.enterNarrowed(c.narrowings(expr.Condition, true))
+	then := c.checkBlockExpecting(expr.Then, expected)
+	c.exitBlock()
+
+	after_then := c.saveAssignments()
+	if alwaysJumps(expr.Then.Stmts) {
+		after_then = nil
+	}
+	c.restoreAssignments(before)
+
+	c.enterNarrowed(c.narrowings(expr.Condition, false))
+	otherwise := c.checkBlockExpecting(expr.Else, expected)
+	c.exitBlock()
+
+	after_else := c.saveAssignments()
+	if alwaysJumps(expr.Else.Stmts) {
+		after_else = nil
+	}
+	c.restoreAssignments(mergeAssignments(after_then, after_else))
+
+	if then == nil || otherwise == nil {
+		return nil
+	}
+
+	if expected != nil && c.assignable(then, expected) && c.assignable(otherwise, expected) {
+		return expected
+	}
+
+	t := c.join(then, otherwise)
+	if t == nil {
+		c.error("Both branches must return the same type", expr).
+			WithLabel(diagnostic.At(blockValue(expr.Then).Position()), fmt.Sprintf("has type %s", then.Name())).
+			WithLabel(diagnostic.At(blockValue(expr.Else).Position()), fmt.Sprintf("has type %s", otherwise.Name()))
+		return nil
+	}
+
+	return t
+}
+
+// Typecheck try expression
+// The value is the value of the body, or of the handler catching an error thrown by it
+func (c *Checker) checkTryExpr(expr *ast.TryExpr) Type {
+	before := c.saveAssignments()
+	body := c.checkBlockExpr(expr.Body)
+	ok := body != nil
+
+	// Variables are assigned after the expression if every block that completes assigns them
+	// Blocks that always jump, e.g. by rethrowing, do not give the value either
+	var after assignments
+	var t Type
+	if !alwaysJumps(expr.Body.Stmts) {
+		after = c.saveAssignments()
+		t = body
+	}
+
+	// Body may throw before or after any of its assignments
+	c.restoreAssignments(before)
+	c.assignedByLoop(assigned(expr.Body))
+	thrown := c.saveAssignments()
+
+	for _, catch := range expr.Catches {
+		c.restoreAssignments(thrown)
+		handler := c.checkCatchClause(catch)
+		if handler == nil {
+			ok = false
+			continue
+		}
+
+		if alwaysJumps(catch.Body.Stmts) {
+			continue
+		}
+		after = mergeAssignments(after, c.saveAssignments())
+
+		if t == nil {
+			t = handler
+			continue
+		}
+
+		joined := c.join(t, handler)
+		if joined == nil {
+			c.error(fmt.Sprintf("Try and catch blocks must have the same type, found %s and %s", t.Name(), handler.Name()), catch.Body)
+			ok = false
+			continue
+		}
+		t = joined
+	}
+
+	// Finally block also runs when an error is not caught
+	if expr.Finally != nil {
+		c.restoreAssignments(mergeAssignments(after, thrown))
+		c.checkBlockExpr(expr.Finally)
+		after = followAssignments(after, c.saveAssignments())
+	}
+
+	if after != nil {
+		c.restoreAssignments(after)
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if t == nil {
+		return NewUnit()
+	}
+
+	return t
+}
+
+// Typecheck handler of try expression
+// Caught type must implement Error and be known at runtime
+func (c *Checker) checkCatchClause(catch *ast.CatchClause) Type {
+	t := c.resolveType(catch.Type)
+	if t == nil {
+		return nil
+	}
+
+	if !checkable(t) {
+		c.error(fmt.Sprintf("Cannot catch type %s at runtime", t.Name()), catch.Type)
+		return nil
+	}
+
+	if !Assignable(t, NewError()) {
+		c.error(fmt.Sprintf("Cannot catch %s, which does not implement Error", t.Name()), catch.Type)
+		return nil
+	}
+
+	c.enterBlock()
+	defer c.exitBlock()
+
+	c.context.define(catch.Name, &variable{
+		name:        catch.Name,
+		kind:        t,
+		mutable:     false,
+		initialized: true,
+	})
+
+	return c.checkBlockExpr(catch.Body)
+}
+
+// Typecheck elvis expression
+// The right operand replaces the left operand if it is null
+func (c *Checker) checkElvisExpr(expr *ast.ElvisExpr) Type {
+	left := c.checkExpr(expr.Left)
+	if left == nil {
+		return nil
+	}
+
+	n, ok := left.(*Nullable)
+	if !ok {
+		c.error(fmt.Sprintf("Left operand of '?:' must be nullable, found %s", left.Name()), expr)
+		return nil
+	}
+
+	right := c.checkExprExpecting(expr.Right, left)
+	if right == nil {
+		return nil
+	}
+
+	// Result is only nullable if the default can be null
+	if Identical(right, n.Elem) {
+		return n.Elem
+	}
+
+	if !c.assignable(right, left) {
+		c.error(fmt.Sprintf("Cannot use %s as default for %s", right.Name(), left.Name()), expr.Right)
+		return nil
+	}
+
+	return left
+}
+
+// Typecheck non-null assertion
+func (c *Checker) checkNonNullExpr(expr *ast.NonNullExpr) Type {
+	t := c.checkExpr(expr.Expr)
+	if t == nil {
+		return nil
+	}
+
+	if _, ok := t.(*Null); ok {
+		c.error("Cannot assert that null is not null", expr)
+		return nil
+	}
+
+	return NonNull(t)
+}
+
+// Typecheck unwrapping of result with '?'
+// Errors are returned from the enclosing function, which must return a result with a compatible error
+func (c *Checker) checkUnwrapExpr(expr *ast.UnwrapExpr) Type {
+	t := c.checkExpr(expr.Expr)
+	if t == nil {
+		return nil
+	}
+
+	r, ok := t.(*Result)
+	if !ok {
+		c.error(fmt.Sprintf("Operand of '?' must be a Result, found %s", t.Name()), expr)
+		return nil
+	}
+
+	// The value of a lambda is the last expression in its body
+	if c.lambda {
+		c.error("'?' not allowed in lambda", expr)
+		return nil
+	}
+
+	if c.function == nil {
+		c.error("'?' outside function", expr)
+		return nil
+	}
+
+	ret, ok := prune(c.function.Return).(*Result)
+	if !ok {
+		c.error(fmt.Sprintf("Cannot use '?' in function returning %s, which is not a Result", c.function.Return.Name()), expr)
+		return nil
+	}
+
+	if !c.assignable(r.Error, ret.Error) {
+		c.error(fmt.Sprintf("Cannot propagate error of type %s from function returning %s", r.Error.Name(), ret.Name()), expr)
+		return nil
+	}
+
+	return r.Value
+}
+
+// Typecheck block expressions
+func (c *Checker) checkBlockExpr(expr *ast.BlockExpr) Type {
+	return c.checkBlockExpecting(expr, nil)
+}
+
+// Typecheck block expression where the type of its value is known from context
+func (c *Checker) checkBlockExpecting(expr *ast.BlockExpr, expected Type) Type {
+	c.enterBlock()
+	defer c.exitBlock()
+
+	// Blocks without a trailing expression evaluate to unit
+	var t Type = NewUnit()
+	for i, n := range expr.Stmts {
+		switch s := n.(type) {
+		case *ast.ExprStmt:
+			if i == len(expr.Stmts)-1 {
+				t = c.checkExprExpecting(s.Expr, expected)
+			} else {
+				c.checkExpr(s.Expr)
+			}
+		default:
+			c.checkStmt(s)
+		}
+	}
+
+	return t
+}
+
+// Get node giving the value of block, its trailing expression if it has one
+func blockValue(block *ast.BlockExpr) ast.Node {
+	if len(block.Stmts) != 0 {
+		if stmt, ok := block.Stmts[len(block.Stmts)-1].(*ast.ExprStmt); ok {
+			return stmt.Expr
+		}
+	}
+
+	return block
+}
+
+// Typecheck identifiers
+func (c *Checker) checkIdent(expr *ast.Ident) Type {
+	sym := c.context.lookup(expr.Name)
+	if sym == nil {
+		c.error(fmt.Sprintf("Undefined identifier: %s", expr.Name), expr)
+		return nil
+	}
+
+	switch v := sym.(type) {
+	case *function, *class:
+	case *enum:
+		c.error(fmt.Sprintf("Enum %s is not a value", v.name), expr)
+		return nil
+	case *iface:
+		c.error(fmt.Sprintf("Interface %s is not a value", v.name), expr)
+		return nil
+	case *variable:
+		if !v.initialized {
+			if v.assigned {
+				c.error(fmt.Sprintf("Identifier may be used before initialized: %s", v.name), expr)
+				return nil
+			}
+
+			c.error(fmt.Sprintf("Identifier used before intialized: %s", v.name), expr)
+			return nil
+		}
+	default:
+		panic(fmt.Sprintf("unexpected types.symbol: %#v", v))
+	}
+
+	return sym.Type()
+}
+
+// Typecheck literal expressions
+func (c *Checker) checkLiteralExpr(expr *ast.LiteralExpr) Type {
+	switch expr.Kind {
+	case token.CHAR:
+		return NewChar()
+	case token.REAL, token.INTEGER, token.BIGINT, token.DECIMAL:
+		return c.checkNumberLiteral(expr, false, nil)
+	case token.STRING:
+		return NewString()
+	case token.TRUE, token.FALSE:
+		return NewBoolean()
+	case token.NULL:
+		return NewNull()
+	default:
+		panic(fmt.Sprintf("Unexpected token.TokenType: %#v", expr.Kind))
+	}
+}
+
+// Typecheck number literal, which may be negated
+// Integer and real literals take the expected type if it has the right kind, otherwise they are int and real
+func (c *Checker) checkNumberLiteral(expr *ast.LiteralExpr, negated bool, expected *Primitive) Type {
+	t := NewNumeric(literalKind(expr.Kind))
+	if expected != nil && literalOf(expr.Kind, expected.kind) {
+		t = expected
+	}
+
+	value := expr.Value
+	if negated {
+		value = "-" + value
+	}
+
+	if !fitsLiteral(value, t.kind) {
+		c.error(fmt.Sprintf("Literal %s out of range for %s", value, t.Name()), expr)
+		return nil
+	}
+
+	return t
+}
+
+// Typecheck unary expressions
+// Negated number literals take the expected numeric type, if any
+func (c *Checker) checkUnaryExpr(expr *ast.UnaryExpr, expected *Primitive) Type {
+	// Negative literals are checked as a whole, so the smallest integer of each type fits
+	var right Type
+	if lit, ok := expr.Expr.(*ast.LiteralExpr); ok && expr.Op.Kind == token.MINUS && token.IsNumber(lit.Kind) {
+		right = c.record(lit, c.checkNumberLiteral(lit, true, expected))
+	} else if expected != nil {
+		right = c.checkExprExpecting(expr.Expr, expected)
+	} else {
+		right = c.checkExpr(expr.Expr)
+	}
+
+	if right == nil {
+		return nil
+	}
+
+	// Operand of unknown type takes the only type the operator allows
+	if _, ok := right.(*Meta); ok {
+		switch expr.Op.Kind {
+		case token.BANG:
+			c.unify(right, NewBoolean())
+		case token.TILDE:
+			c.unify(right, NewInteger())
+		default:
+			c.error(fmt.Sprintf("Cannot infer operand type of %s", expr.Op.Value), expr)
+			return nil
+		}
+		right = prune(right)
+	}
+
+	p, ok := right.(*Primitive)
+	if !ok {
+		// TODO: add type error
+		return nil
+	}
+
+	switch expr.Op.Kind {
+	case token.BANG:
+		switch p.kind {
+		case Boolean:
+			return p
+		default:
+			c.operatorError(expr, p)
+			return nil
+		}
+
+	case token.MINUS:
+		if p.kind.isNumeric() && !p.kind.isUnsigned() {
+			return p
+		}
+
+		c.operatorError(expr, p)
+		return nil
+
+	case token.TILDE:
+		if p.kind.isInteger() {
+			return p
+		}
+
+		c.operatorError(expr, p)
+		return nil
+
+	default:
+		panic(fmt.Sprintf("unexpected token.TokenType: %#v", expr.Op.Kind))
+	}
+}
+
+// Typecheck binary expressions
+func (c *Checker) checkBinaryExpr(expr *ast.BinaryExpr) Type {
+	// Number literals take the numeric type of the other operand
+	left := c.checkExpr(expr.Left)
+	right := c.checkExprExpecting(expr.Right, left)
+	if p, ok := right.(*Primitive); ok && p.kind.isNumeric() && isNumberLiteral(expr.Left) {
+		left = c.checkExprExpecting(expr.Left, right)
+	}
 
 	// Got type error deeper in tree
 	if left == nil || right == nil {
 		return nil
 	}
 
+	// Operand of unknown type takes the type of the other operand
+	_, l_unknown := left.(*Meta)
+	_, r_unknown := right.(*Meta)
+	if l_unknown && r_unknown {
+		c.error(fmt.Sprintf("Cannot infer operand types of %s", expr.Op.Value), expr)
+		return nil
+	}
+
+	// Compared operands have the same type, so unknowns in one are solved by the other, e.g. in r == Ok(1)
+	equality := expr.Op.Kind == token.EQUAL_EQUAL || expr.Op.Kind == token.BANG_EQUAL
+	if l_unknown || r_unknown || equality && (!solved(left) || !solved(right)) {
+		c.unify(left, right)
+		left, right = prune(left), prune(right)
+	}
+
+	t := binaryType(expr.Op.Kind, left, right)
+	if t == nil {
+		c.operatorError(expr, left, right)
+		return nil
+	}
+
+	return t
+}
+
+// Get result type of applying binary operator to operands
+// Returns nil if operator is not defined for the operand types
+func binaryType(op token.TokenType, left Type, right Type) Type {
+	// Data classes and enums are compared by their fields
+	if op == token.EQUAL_EQUAL || op == token.BANG_EQUAL {
+		if cls, ok := left.(*Class); ok && cls.Data && Identical(left, right) {
+			return NewBoolean()
+		}
+
+		if _, ok := left.(*Enum); ok && Identical(left, right) {
+			return NewBoolean()
+		}
+
+		// Lists and maps are compared by their elements
+		if list, ok := left.(*List); ok && Identical(left, right) {
+			return binaryType(op, list.Elem, list.Elem)
+		}
+
+		if m, ok := left.(*Map); ok && Identical(left, right) {
+			return binaryType(op, m.Value, m.Value)
+		}
+
+		// Results are compared by their values and errors
+		if r, ok := left.(*Result); ok && Identical(left, right) {
+			value, err := prune(r.Value), prune(r.Error)
+			if binaryType(op, value, value) == nil || binaryType(op, err, err) == nil {
+				return nil
+			}
+			return NewBoolean()
+		}
+
+		// Values of the same type parameter can be compared
+		if _, ok := left.(*TypeVar); ok && Identical(left, right) {
+			return NewBoolean()
+		}
+
+		// Nullable values are compared with null or values of the same type
+		_, l_null := left.(*Null)
+		_, r_null := right.(*Null)
+		_, l_nullable := left.(*Nullable)
+		_, r_nullable := right.(*Nullable)
+		if l_null || r_null {
+			if l_null && r_null || l_nullable || r_nullable {
+				return NewBoolean()
+			}
+			return nil
+		}
+
+		if l_nullable || r_nullable {
+			return binaryType(op, NonNull(left), NonNull(right))
+		}
+	}
+
 	p_left, l_ok := left.(*Primitive)
 	p_right, r_ok := right.(*Primitive)
 	if !l_ok || !r_ok {
-		// TODO: Add type error
-		fmt.Printf("TODO: do thingy")
 		return nil
 	}
 
-	switch expr.Op.Kind {
+	// Numbers of different types must be converted explicitly
+	if p_left.kind != p_right.kind {
+		return nil
+	}
+
+	kind := p_left.kind
+	switch op {
 	case token.PLUS:
-		switch p_left.kind {
-		case Int, Real, String:
-			if p_left.kind == p_right.kind {
-				return left
-			}
+		if kind.isNumeric() || kind == String {
+			return left
 		}
 	case token.MINUS, token.SLASH, token.STAR, token.STAR_STAR:
-		switch p_left.kind {
-		case Int, Real:
-			if p_left.kind == p_right.kind {
-				return left
-			}
+		if kind.isNumeric() {
+			return left
 		}
-	case token.PERCENT, token.CARET:
-		switch p_left.kind {
-		case Int:
-			if p_right.kind == Int {
-				return left
-			}
+	case token.PERCENT, token.CARET, token.AND, token.OR, token.TILDE:
+		if kind.isInteger() {
+			return left
 		}
 	case token.EQUAL_EQUAL, token.BANG_EQUAL:
-		switch p_left.kind {
-		case Boolean, Char, Int, Real, String:
-			if p_left.kind == p_right.kind {
-				return NewBoolean()
-			}
+		if kind.isNumeric() || kind == Boolean || kind == Char || kind == String {
+			return NewBoolean()
 		}
 	case token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
-		switch p_left.kind {
-		case Char, Int, Real, String:
-			if p_left.kind == p_right.kind {
-				return NewBoolean()
-			}
+		if kind.isNumeric() || kind == Char || kind == String {
+			return NewBoolean()
 		}
 	case token.LAND, token.LOR:
-		switch p_left.kind {
-		case Boolean:
-			if p_right.kind == Boolean {
-				return NewBoolean()
-			}
-		}
-	case token.AND, token.OR:
-		switch p_left.kind {
-		case Int:
-			if p_right.kind == Int {
-				return NewBoolean()
-			}
+		if kind == Boolean {
+			return NewBoolean()
 		}
 	}
 
-	c.operatorError(expr)
 	return nil
 }
 
 // Create type error with message
-func (c *Checker) error(message string, node ast.Node) {
-	err := errors.New(fmt.Sprintf("%s:%d:%d - %s", c.file, node.Position().Row, node.Position().Column, message))
+func (c *Checker) error(message string, node ast.Node) *diagnostic.Diagnostic {
+	return c.errorAt(message, node.Position())
+}
+
+// Create type error with message at position
+func (c *Checker) errorAt(message string, pos token.Position) *diagnostic.Diagnostic {
+	err := diagnostic.NewError(c.file, diagnostic.At(pos), message)
 	c.Errors = append(c.Errors, err)
+	return err
 }
 
-// Create type error for operator expression
-func (c *Checker) operatorError(expr ast.Expr) {
+// Create type error for operator expression with the already checked types of its operands
+func (c *Checker) operatorError(expr ast.Expr, operands ...Type) {
 	var message string
 	switch n := expr.(type) {
 	case *ast.BinaryExpr:
-		message = fmt.Sprintf("Invalid operation: %s (mismatched types %s and %s)", n, c.checkExpr(n.Left), c.checkExpr(n.Right))
+		message = fmt.Sprintf("Invalid operation: %s (%s)", n, operatorMismatch(n.Op.Value, operands[0], operands[1]))
 	case *ast.UnaryExpr:
-		message = fmt.Sprintf("Invalid operation: %s (mismatched type %s)", n, c.checkExpr(n.Expr))
+		message = fmt.Sprintf("Invalid operation: %s (operator %s not defined for %s)", n, n.Op.Value, operands[0].Name())
 	default:
 		panic(fmt.Sprintf("unexpected ast.Expr: %#v", expr))
 	}
 	c.error(message, expr)
 }
 
+// Explain why binary operator is not defined for operands of types left and right
+func operatorMismatch(op string, left Type, right Type) string {
+	if cls, ok := left.(*Class); ok && !cls.Data && (op == "==" || op == "!=") {
+		return fmt.Sprintf("equality is only defined for data classes, %s is not a data class", cls.Name())
+	}
+
+	if Identical(left, right) {
+		return fmt.Sprintf("operator %s not defined for %s", op, left)
+	}
+
+	return fmt.Sprintf("mismatched types %s and %s", left, right)
+}
+
 // Enter new synctactic block
 func (c *Checker) enterBlock() {
 	c.context = newContextWithParent(c.context)
@@ -463,3 +2899,546 @@ func (c *Checker) enterBlock() {
 func (c *Checker) exitBlock() {
 	c.context = c.context.parent
 }
+
+// Enter block where variables have the given narrowed types
+func (c *Checker) enterNarrowed(narrowed map[string]Type) {
+	c.enterBlock()
+	c.narrow(narrowed)
+}
+
+// Give variables narrowed types in the current block
+func (c *Checker) narrow(narrowed map[string]Type) {
+	for name, t := range narrowed {
+		// Variables assigned by lambdas and functions may change at any call
+		v := c.context.lookup(name).(*variable)
+		if v.declared().captured {
+			continue
+		}
+
+		c.context.define(name, &variable{
+			name:        name,
+			kind:        t,
+			mutable:     v.mutable,
+			initialized: true,
+			origin:      v,
+		})
+	}
+}
+
+// Narrow types for the rest of the block after an if statement
+// Variables assigned in the completing branch are left as they are
+func (c *Checker) narrowAfter(narrowed map[string]Type, branch *ast.BlockStmt) {
+	if branch != nil {
+		for name := range assigned(branch) {
+			delete(narrowed, name)
+		}
+	}
+
+	c.narrow(narrowed)
+}
+
+// Forget narrowed type of variable, which no longer holds after it is reassigned
+func (c *Checker) invalidate(name string) {
+	v, ok := c.context.lookup(name).(*variable)
+	if !ok {
+		return
+	}
+
+	declared := v.declared()
+	for ; v.origin != nil; v = v.origin {
+		v.kind = declared.kind
+	}
+}
+
+// Forget narrowed types of all variables with the given names
+func (c *Checker) invalidateAll(names map[string]bool) {
+	for name := range names {
+		c.invalidate(name)
+	}
+}
+
+// Give mutable variables narrowed outside of a closure their declared types in it,
+// as they may be reassigned before the closure runs
+func (c *Checker) forgetMutableNarrowings() {
+	seen := map[string]bool{}
+	for ctx := c.context.parent; ctx != nil; ctx = ctx.parent {
+		for name, sym := range ctx.symbols {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			if v, ok := sym.(*variable); ok && v.mutable && v.origin != nil {
+				c.context.define(name, v.declared())
+			}
+		}
+	}
+}
+
+// Find variables with narrower types when condition evaluates to truthy
+// Maps names of variables to their narrowed types
+func (c *Checker) narrowings(cond ast.Expr, truthy bool) map[string]Type {
+	narrowed := map[string]Type{}
+
+	switch e := cond.(type) {
+	case *ast.GroupingExpr:
+		return c.narrowings(e.Expr, truthy)
+	case *ast.UnaryExpr:
+		if e.Op.Kind == token.BANG {
+			return c.narrowings(e.Expr, !truthy)
+		}
+	case *ast.BinaryExpr:
+		if e.Op.Kind != token.EQUAL_EQUAL && e.Op.Kind != token.BANG_EQUAL {
+			break
+		}
+
+		// 'x != null' narrows when true, 'x == null' when false
+		if (e.Op.Kind == token.BANG_EQUAL) != truthy {
+			break
+		}
+
+		ident, ok := e.Left.(*ast.Ident)
+		other := e.Right
+		if !ok {
+			ident, ok = e.Right.(*ast.Ident)
+			other = e.Left
+		}
+
+		if lit, isLit := other.(*ast.LiteralExpr); !ok || !isLit || lit.Kind != token.NULL {
+			break
+		}
+
+		if v, ok := c.context.lookup(ident.Name).(*variable); ok {
+			if n, ok := prune(v.kind).(*Nullable); ok {
+				narrowed[ident.Name] = n.Elem
+			}
+		}
+	case *ast.IsExpr:
+		// 'x is T' narrows when true
+		ident, ok := e.Expr.(*ast.Ident)
+		if !ok || !truthy {
+			break
+		}
+
+		if _, ok := c.context.lookup(ident.Name).(*variable); ok {
+			if t := c.resolveType(e.Type); t != nil {
+				narrowed[ident.Name] = t
+			}
+		}
+	case *ast.LogicalExpr:
+		// Both operands are known when 'a && b' is true or 'a || b' is false
+		if (e.Op.Kind == token.LAND) != truthy {
+			break
+		}
+
+		for _, operand := range []ast.Expr{e.Left, e.Right} {
+			for name, t := range c.narrowings(operand, truthy) {
+				narrowed[name] = t
+			}
+		}
+	}
+
+	return narrowed
+}
+
+// Find type that can hold values of both types, solving unknowns in them if needed
+// Returns nil if there is no such type
+func (c *Checker) join(a Type, b Type) Type {
+	if (!solved(a) || !solved(b)) && c.unify(a, b) {
+		return prune(a)
+	}
+
+	return join(prune(a), prune(b))
+}
+
+// Find type that can hold values of both types
+// Returns nil if there is no such type
+func join(a Type, b Type) Type {
+	if Assignable(a, b) {
+		return b
+	}
+
+	if Assignable(b, a) {
+		return a
+	}
+
+	if _, ok := a.(*Null); ok {
+		return NewNullable(b)
+	}
+
+	if _, ok := b.(*Null); ok {
+		return NewNullable(a)
+	}
+
+	// Nullable types join to the nullable join of their values
+	_, a_nullable := a.(*Nullable)
+	_, b_nullable := b.(*Nullable)
+	if a_nullable || b_nullable {
+		if t := join(NonNull(a), NonNull(b)); t != nil {
+			return NewNullable(t)
+		}
+		return nil
+	}
+
+	// Classes join to the interface they both implement, if there is only one
+	l, l_ok := a.(*Class)
+	r, r_ok := b.(*Class)
+	if l_ok && r_ok {
+		shared := []*Interface{}
+		for _, i := range l.Interfaces() {
+			if slices.Contains(r.Interfaces(), i) {
+				shared = append(shared, i)
+			}
+		}
+
+		if len(shared) == 1 {
+			return shared[0]
+		}
+	}
+
+	return nil
+}
+
+// Loop enclosing the statements being checked
+type loop struct {
+	label  string      // "" if unlabeled
+	breaks assignments // Assignment state at break statements leaving the loop (nil if none)
+}
+
+// Enter body of loop with label
+func (c *Checker) enterLoop(label string) *loop {
+	l := &loop{label: label}
+	c.loops = append(c.loops, l)
+	return l
+}
+
+// Exit body of loop
+func (c *Checker) exitLoop() {
+	c.loops = c.loops[:len(c.loops)-1]
+}
+
+// Check if every path through statements ends in a return
+func alwaysReturns(stmts []ast.Stmt) bool {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.ReturnStmt, *ast.ThrowStmt:
+			return true
+		case *ast.BlockStmt:
+			if alwaysReturns(s.Stmts) {
+				return true
+			}
+		case *ast.IfStmt:
+			if s.Else != nil && alwaysReturns(s.Then.Stmts) && alwaysReturns(s.Else.Stmts) {
+				return true
+			}
+		case *ast.ExprStmt:
+			if try, ok := s.Expr.(*ast.TryExpr); ok && tryAlways(try, alwaysReturns) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Check if statements always return, break or continue,
+// so code after them is never reached
+func alwaysJumps(stmts []ast.Stmt) bool {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.ReturnStmt, *ast.ThrowStmt, *ast.BreakStmt, *ast.ContinueStmt:
+			return true
+		case *ast.BlockStmt:
+			if alwaysJumps(s.Stmts) {
+				return true
+			}
+		case *ast.IfStmt:
+			if s.Else != nil && alwaysJumps(s.Then.Stmts) && alwaysJumps(s.Else.Stmts) {
+				return true
+			}
+		case *ast.ExprStmt:
+			if try, ok := s.Expr.(*ast.TryExpr); ok && tryAlways(try, alwaysJumps) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Check if every path through try expression satisfies check
+// The finally block is on every path, otherwise the body and every handler must satisfy it
+func tryAlways(expr *ast.TryExpr, check func([]ast.Stmt) bool) bool {
+	if expr.Finally != nil && check(expr.Finally.Stmts) {
+		return true
+	}
+
+	if !check(expr.Body.Stmts) {
+		return false
+	}
+
+	for _, catch := range expr.Catches {
+		if !check(catch.Body.Stmts) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check if no value of one type can have the other type
+// Values of interface types can be of any class implementing them, so only checks involving
+// another kind of type can be known to fail
+func disjoint(a, b Type) bool {
+	if Assignable(a, b) || Assignable(b, a) {
+		return false
+	}
+
+	return concrete(NonNull(prune(a))) || concrete(NonNull(prune(b)))
+}
+
+// Check if type is not an interface, so its values have exactly that type
+func concrete(t Type) bool {
+	switch t.(type) {
+	case *Primitive, *Class, *Enum:
+		return true
+	default:
+		return false
+	}
+}
+
+// Check if values of type can be recognized at runtime
+// Type arguments are not known at runtime
+func checkable(t Type) bool {
+	switch x := t.(type) {
+	case *Primitive, *Enum, *Interface:
+		return true
+	case *Class:
+		return len(x.Args) == 0
+	case *Nullable:
+		return checkable(x.Elem)
+	default:
+		return false
+	}
+}
+
+// Find names of variables assigned anywhere in node, including nested closures
+func assigned(node ast.Node) map[string]bool {
+	names := map[string]bool{}
+	collectAssigned(node, names)
+	return names
+}
+
+// Find names of variables assigned in loop body on paths that reach the next iteration,
+// by completing the body or continuing the loop with label
+// Paths that break out of the loop, return or throw are not seen by later iterations
+func iterationAssigned(body *ast.BlockStmt, label string) map[string]bool {
+	it := &iteration{labels: []string{label}, reached: []map[string]bool{{}}}
+	maps.Copy(it.reached[0], it.collect(body.Stmts, map[string]bool{}))
+	return it.reached[0]
+}
+
+// Loops entered while finding assignments reaching the next iteration of the outermost one
+type iteration struct {
+	labels  []string          // Labels of loops, innermost last
+	reached []map[string]bool // Names assigned on paths reaching the next iteration or the end of each loop
+}
+
+// Add names assigned on paths through statements that jump to a loop to the loop,
+// where flow holds names assigned earlier on the path
+// Returns names assigned on paths that complete the statements, or nil if none do
+func (it *iteration) collect(stmts []ast.Stmt, flow map[string]bool) map[string]bool {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.ReturnStmt, *ast.ThrowStmt:
+			return nil
+		case *ast.BreakStmt:
+			// Breaking the outermost loop leaves it, breaking a nested loop continues after it
+			if i := it.target(s.Label); i > 0 {
+				maps.Copy(it.reached[i], flow)
+			}
+			return nil
+		case *ast.ContinueStmt:
+			if i := it.target(s.Label); i >= 0 {
+				maps.Copy(it.reached[i], flow)
+			}
+			return nil
+		case *ast.BlockStmt:
+			flow = it.collect(s.Stmts, flow)
+		case *ast.IfStmt:
+			collectAssigned(s.Condition, flow)
+			then := it.collect(s.Then.Stmts, maps.Clone(flow))
+			otherwise := flow
+			if s.Else != nil {
+				otherwise = it.collect(s.Else.Stmts, maps.Clone(flow))
+			}
+
+			if then == nil {
+				flow = otherwise
+			} else {
+				maps.Copy(then, otherwise)
+				flow = then
+			}
+		case *ast.WhileStmt:
+			collectAssigned(s.Condition, flow)
+			flow = it.loop(s.Label, s.Block, flow)
+		case *ast.ForStmt:
+			collectAssigned(s.Iterable, flow)
+			flow = it.loop(s.Label, s.Block, flow)
+		default:
+			collectAssigned(s, flow)
+		}
+
+		if flow == nil {
+			return nil
+		}
+	}
+
+	return flow
+}
+
+// Collect names assigned in nested loop, which may run any number of times
+// Returns names assigned on paths that continue after the loop
+func (it *iteration) loop(label string, body *ast.BlockStmt, flow map[string]bool) map[string]bool {
+	it.labels = append(it.labels, label)
+	it.reached = append(it.reached, maps.Clone(flow))
+	maps.Copy(it.reached[len(it.reached)-1], it.collect(body.Stmts, maps.Clone(flow)))
+
+	after := it.reached[len(it.reached)-1]
+	it.labels = it.labels[:len(it.labels)-1]
+	it.reached = it.reached[:len(it.reached)-1]
+
+	return after
+}
+
+// Index of loop targeted by break or continue with label, or -1 if it is outside the outermost loop
+func (it *iteration) target(label string) int {
+	for i := len(it.labels) - 1; i >= 0; i-- {
+		if label == "" || it.labels[i] == label {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Nil expressions are skipped
+func collectAssigned(node ast.Node, names map[string]bool) {
+	switch n := node.(type) {
+	case nil:
+	case *ast.AssignmentStmt:
+		names[n.Name] = true
+		collectAssigned(n.Value, names)
+	case *ast.BlockStmt:
+		for _, s := range n.Stmts {
+			collectAssigned(s, names)
+		}
+	case *ast.BlockExpr:
+		for _, s := range n.Stmts {
+			collectAssigned(s, names)
+		}
+	case *ast.ExprStmt:
+		collectAssigned(n.Expr, names)
+	case *ast.VarDeclaration:
+		collectAssigned(n.Value, names)
+	case *ast.IndexAssignmentStmt:
+		collectAssigned(n.Object, names)
+		collectAssigned(n.Index, names)
+		collectAssigned(n.Value, names)
+	case *ast.FieldAssignmentStmt:
+		collectAssigned(n.Object, names)
+		collectAssigned(n.Value, names)
+	case *ast.IfStmt:
+		collectAssigned(n.Condition, names)
+		collectAssigned(n.Then, names)
+		if n.Else != nil {
+			collectAssigned(n.Else, names)
+		}
+	case *ast.WhileStmt:
+		collectAssigned(n.Condition, names)
+		collectAssigned(n.Block, names)
+	case *ast.ForStmt:
+		collectAssigned(n.Iterable, names)
+		collectAssigned(n.Block, names)
+	case *ast.ReturnStmt:
+		collectAssigned(n.Value, names)
+	case *ast.ThrowStmt:
+		collectAssigned(n.Value, names)
+	case *ast.FunDeclaration:
+		collectAssigned(n.Body, names)
+	case *ast.BinaryExpr:
+		collectAssigned(n.Left, names)
+		collectAssigned(n.Right, names)
+	case *ast.LogicalExpr:
+		collectAssigned(n.Left, names)
+		collectAssigned(n.Right, names)
+	case *ast.GroupingExpr:
+		collectAssigned(n.Expr, names)
+	case *ast.UnaryExpr:
+		collectAssigned(n.Expr, names)
+	case *ast.IfExpr:
+		collectAssigned(n.Condition, names)
+		collectAssigned(n.Then, names)
+		collectAssigned(n.Else, names)
+	case *ast.TryExpr:
+		collectAssigned(n.Body, names)
+		for _, catch := range n.Catches {
+			collectAssigned(catch.Body, names)
+		}
+		if n.Finally != nil {
+			collectAssigned(n.Finally, names)
+		}
+	case *ast.CallExpr:
+		collectAssigned(n.Callee, names)
+		for _, arg := range n.Args {
+			collectAssigned(arg, names)
+		}
+	case *ast.LambdaExpr:
+		collectAssigned(n.Body, names)
+	case *ast.RangeExpr:
+		collectAssigned(n.Start, names)
+		collectAssigned(n.End, names)
+		collectAssigned(n.Step, names)
+	case *ast.MatchExpr:
+		collectAssigned(n.Subject, names)
+		for _, arm := range n.Arms {
+			collectAssigned(arm.Guard, names)
+			collectAssigned(arm.Body, names)
+		}
+	case *ast.ListLiteral:
+		for _, e := range n.Elements {
+			collectAssigned(e, names)
+		}
+	case *ast.MapLiteral:
+		for i := range n.Keys {
+			collectAssigned(n.Keys[i], names)
+			collectAssigned(n.Values[i], names)
+		}
+	case *ast.IndexExpr:
+		collectAssigned(n.Object, names)
+		collectAssigned(n.Index, names)
+	case *ast.GetExpr:
+		collectAssigned(n.Object, names)
+	case *ast.ElvisExpr:
+		collectAssigned(n.Left, names)
+		collectAssigned(n.Right, names)
+	case *ast.NonNullExpr:
+		collectAssigned(n.Expr, names)
+	case *ast.IsExpr:
+		collectAssigned(n.Expr, names)
+	case *ast.CastExpr:
+		collectAssigned(n.Expr, names)
+	case *ast.UnwrapExpr:
+		collectAssigned(n.Expr, names)
+	}
+}
+
+// Check if expression is a number literal, possibly negated
+func isNumberLiteral(expr ast.Expr) bool {
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op.Kind == token.MINUS {
+		expr = u.Expr
+	}
+
+	lit, ok := expr.(*ast.LiteralExpr)
+	return ok && token.IsNumber(lit.Kind)
+}
@@ -0,0 +1,258 @@
+package types
+
+import (
+	"interpreter/lexer"
+	"interpreter/parser"
+	"strings"
+	"testing"
+)
+
+func TestNarrowCapturedVariable(t *testing.T) {
+	input := `
+var x: int? = 5;
+val f = { -> x = null; };
+if x != null { f(); x + 1; }
+`
+
+	errors := check(t, input)
+	if len(errors) == 0 {
+		t.Errorf("Expected error for variable assigned in lambda")
+	}
+}
+
+func TestNarrowVariableReadInLambda(t *testing.T) {
+	input := `
+var x: int? = 5;
+val f = { -> x; };
+if x != null { f(); x + 1; }
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestNarrowVariableAssignedInFunction(t *testing.T) {
+	input := `
+var x: int? = 5;
+fun f() { x = null; }
+if x != null { f(); x + 1; }
+`
+
+	errors := check(t, input)
+	if len(errors) == 0 {
+		t.Errorf("Expected error for variable assigned in function")
+	}
+}
+
+func TestNarrowLambdaParameter(t *testing.T) {
+	input := `
+var x: int? = 5;
+val f = { x: int? -> x; };
+if x != null { f(null); x + 1; }
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestAssignBeforeBreak(t *testing.T) {
+	input := `
+val c = true;
+val x: int;
+while true { if c { x = 1; break; } }
+x;
+var y: int;
+while true { y = 1; break; }
+y;
+val z: int;
+outer@ while true { while c { z = 1; break@outer; } }
+z;
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestAssignBeforeBreakInConditionalLoop(t *testing.T) {
+	input := `
+val c = true;
+var x: int;
+while c { x = 1; break; }
+x;
+`
+
+	errors := check(t, input)
+	if len(errors) == 0 {
+		t.Errorf("Expected error for variable not assigned when condition is false")
+	}
+}
+
+func TestAssignBeforeContinue(t *testing.T) {
+	input := `
+val c = true;
+val x: int;
+while true { if c { x = 1; continue; } break; }
+`
+
+	errors := check(t, input)
+	if len(errors) == 0 {
+		t.Errorf("Expected error for immutable variable assigned in later iterations")
+	}
+}
+
+func TestIntegerLiteralKeyAndIndex(t *testing.T) {
+	input := `
+val m: Map<u8, int> = [1: 2];
+m[1];
+var n: Map<i16, string> = [:];
+n[3] = "a";
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestSizedRange(t *testing.T) {
+	input := "(0 as u8)..5;"
+
+	errors := check(t, input)
+	if len(errors) != 1 {
+		t.Errorf("Expected one error for range of u8, found %d", len(errors))
+	}
+}
+
+func TestCompareResults(t *testing.T) {
+	input := `
+val r: Result<int, string> = Ok(1);
+r == Ok(1);
+Err("a") != r;
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestOperatorErrorReportedOnce(t *testing.T) {
+	input := `"a" - (1 + "b");`
+
+	errors := check(t, input)
+	if len(errors) != 1 {
+		t.Errorf("Expected one error, found %d", len(errors))
+	}
+}
+
+func TestFailedPropagationReportedOnce(t *testing.T) {
+	input := `
+fun f(): Result<int, string> {
+    val x = parseInt("1")?;
+    return Ok(x);
+}
+`
+
+	errors := check(t, input)
+	if len(errors) != 1 {
+		t.Errorf("Expected one error, found %d", len(errors))
+		for i, err := range errors {
+			t.Logf("Error %d: %v", i, err)
+		}
+	}
+}
+
+func TestCallInferredFromExpectedType(t *testing.T) {
+	input := `
+class Box<T>(val v: T) { }
+fun <T> single(x: T): [T] { return [x]; }
+val b: Box<int?> = Box(1);
+fun f(x: Box<int?>): int? { return x.v; }
+f(Box(1));
+val xs: [int?] = single(1);
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestCallOfSubtypeOfExpectedType(t *testing.T) {
+	input := `
+interface S { fun s(): int; }
+class C() : S { fun s(): int { return 1; } }
+fun <T> id(x: T): T { return x; }
+val s: S = id(C());
+val xs: [S] = [id(C())];
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestIsBetweenInterfaces(t *testing.T) {
+	input := `
+interface S { fun s(): int; }
+interface T { fun t(): int; }
+class Both() : S, T { fun s(): int { return 1; } fun t(): int { return 2; } }
+fun f(x: S): int { if x is T { return 1; } return 0; }
+fun g(): int { try { return f(Both()); } catch (e: Error) { if e is S { return 1; } return 0; } }
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestIsNeverClass(t *testing.T) {
+	input := `
+interface S { fun s(): int; }
+class Other() { }
+fun f(x: S): boolean { return x is Other; }
+`
+
+	errors := check(t, input)
+	if len(errors) != 1 {
+		t.Errorf("Expected one error for class not implementing interface, found %d", len(errors))
+	}
+}
+
+func TestCastBetweenInterfaces(t *testing.T) {
+	input := `
+interface S { fun s(): int; }
+interface T { fun t(): int; }
+fun f(x: S): T? { return x as? T; }
+`
+
+	verifyNoErrors(t, check(t, input))
+}
+
+func TestRedefinitionWithDifferentType(t *testing.T) {
+	input := `
+val a = 1;
+val a = "s";
+`
+
+	errors := check(t, input)
+	if len(errors) != 1 {
+		t.Fatalf("Expected one error, found %d", len(errors))
+	}
+
+	if got := errors[0].Error(); !strings.Contains(got, "Redefinition of a") || !strings.Contains(got, ":3:1") {
+		t.Errorf("Expected redefinition error at declaration, found %q", got)
+	}
+}
+
+// Lex, parse and typecheck input, returning errors of the checker
+func check(t *testing.T, input string) []error {
+	tokens, errors := lexer.NewLexer([]byte(input), "test").Tokenize()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected lexer errors: %v", errors)
+	}
+
+	program, errors := parser.NewParser(tokens, "test").Parse()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected parser errors: %v", errors)
+	}
+
+	// Every test starts from a fresh global context
+	outer = nil
+	checker := NewChecker("test")
+	checker.Visit(program)
+
+	return checker.Errors
+}
+
+func verifyNoErrors(t *testing.T, errors []error) {
+	t.Helper()
+
+	for i, err := range errors {
+		t.Errorf("Error %d: %v", i, err)
+	}
+}
@@ -0,0 +1,161 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type of instances of user defined class
+// Generic classes are instantiated with type arguments, e.g. Box<int>
+type Class struct {
+	name       string
+	Data       bool       // Data classes are compared by fields and can be copied
+	TypeParams []*TypeVar // Type parameters of generic class
+	Args       []Type     // Type arguments, the type parameters themselves for the declared class
+	origin     *Class     // Declared class this is an instantiation of
+	fields     []*Field
+	methods    map[string]*Function
+	interfaces []*Interface
+}
+
+// Field of class
+type Field struct {
+	Name    string
+	Type    Type
+	Mutable bool
+}
+
+func NewClass(name string) *Class {
+	c := &Class{
+		name:    name,
+		fields:  []*Field{},
+		methods: map[string]*Function{},
+	}
+	c.origin = c
+
+	return c
+}
+
+func (c *Class) Name() string {
+	if len(c.Args) == 0 {
+		return c.name
+	}
+
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = arg.Name()
+	}
+
+	return fmt.Sprintf("%s<%s>", c.name, strings.Join(args, ", "))
+}
+
+func (c *Class) String() string {
+	return typeString(c)
+}
+
+// Make class generic over type parameters
+func (c *Class) SetTypeParams(params []*TypeVar) {
+	c.TypeParams = params
+	c.Args = make([]Type, len(params))
+	for i, p := range params {
+		c.Args[i] = p
+	}
+}
+
+// Create class with type parameters replaced by type arguments
+func (c *Class) Instantiate(args []Type) *Class {
+	return &Class{
+		name:       c.origin.name,
+		Data:       c.origin.Data,
+		TypeParams: c.origin.TypeParams,
+		Args:       args,
+		origin:     c.origin,
+	}
+}
+
+// Map type parameters of declared class to type arguments of this instantiation
+func (c *Class) substitution() map[*TypeVar]Type {
+	subst := map[*TypeVar]Type{}
+	for i, p := range c.TypeParams {
+		subst[p] = c.Args[i]
+	}
+
+	return subst
+}
+
+// Add field to declared class
+func (c *Class) AddField(field *Field) {
+	c.fields = append(c.fields, field)
+}
+
+// Add method to declared class
+func (c *Class) AddMethod(name string, signature *Function) {
+	c.methods[name] = signature
+}
+
+// Add interface implemented by declared class
+func (c *Class) AddInterface(i *Interface) {
+	c.interfaces = append(c.interfaces, i)
+}
+
+// Get interfaces implemented by class
+func (c *Class) Interfaces() []*Interface {
+	return c.origin.interfaces
+}
+
+// Get fields in constructor order
+func (c *Class) Fields() []*Field {
+	if c.origin == c {
+		return c.fields
+	}
+
+	subst := c.substitution()
+	fields := make([]*Field, len(c.origin.fields))
+	for i, f := range c.origin.fields {
+		fields[i] = &Field{
+			Name:    f.Name,
+			Type:    Substitute(f.Type, subst),
+			Mutable: f.Mutable,
+		}
+	}
+
+	return fields
+}
+
+// Get field with name, or nil if class has no such field
+func (c *Class) Field(name string) *Field {
+	for _, f := range c.Fields() {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// Get signature of method with name, or nil if class has no such method
+func (c *Class) Method(name string) *Function {
+	method, ok := c.origin.methods[name]
+	if !ok || method == nil || c.origin == c {
+		return method
+	}
+
+	return Substitute(method, c.substitution()).(*Function)
+}
+
+// Type of primary constructor, taking fields in order
+// Constructor of declared generic class infers its type arguments
+func (c *Class) Constructor() *Function {
+	fields := c.Fields()
+	params := make([]Type, len(fields))
+	for i, f := range fields {
+		params[i] = f.Type
+	}
+
+	f := NewFunction(params, c)
+	if c.origin == c {
+		f.TypeParams = c.TypeParams
+	}
+
+	return f
+}
@@ -15,10 +15,17 @@ type context struct {
 
 func newContext() *context {
 	if outer == nil {
+		// Builtin functions are in an enclosing context, so programs can redefine them
+		builtins := &context{
+			symbols: getBuiltins(),
+			types:   getBuiltinTypes(),
+			parent:  nil,
+		}
+
 		outer = &context{
 			symbols: map[string]symbol{},
 			types:   getPrimitives(),
-			parent:  nil,
+			parent:  builtins,
 		}
 	}
 
@@ -62,6 +69,20 @@ func (env *context) lookup(name string) symbol {
 	return nil
 }
 
+// Lookup type with name
+// Look through all enclosing scopes
+func (c *context) lookupType(name string) Type {
+	if t, ok := c.types[name]; ok {
+		return t
+	}
+
+	if c.parent != nil {
+		return c.parent.lookupType(name)
+	}
+
+	return nil
+}
+
 func (c *context) error(message string) error {
 	return errors.New(message)
 }
@@ -0,0 +1,39 @@
+package types
+
+// Type of enums, whose values are one of a fixed set of variants
+type Enum struct {
+	name     string
+	Variants []*Variant // Variants in declaration order
+}
+
+// Variant of enum, optionally carrying values
+type Variant struct {
+	Name   string
+	Fields []*Field
+}
+
+func NewEnum(name string) *Enum {
+	return &Enum{
+		name:     name,
+		Variants: []*Variant{},
+	}
+}
+
+func (e *Enum) Name() string {
+	return e.name
+}
+
+func (e *Enum) String() string {
+	return typeString(e)
+}
+
+// Get variant with name, or nil if enum has no such variant
+func (e *Enum) Variant(name string) *Variant {
+	for _, v := range e.Variants {
+		if v.Name == name {
+			return v
+		}
+	}
+
+	return nil
+}
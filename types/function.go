@@ -0,0 +1,43 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type of function values
+type Function struct {
+	TypeParams []*TypeVar // Type parameters inferred at each call (generic functions only)
+	Params     []Type     // Types of parameters
+	Return     Type       // Type of returned value
+}
+
+func NewFunction(params []Type, ret Type) *Function {
+	return &Function{
+		Params: params,
+		Return: ret,
+	}
+}
+
+func (f *Function) Name() string {
+	params := make([]string, len(f.Params))
+	for i, p := range f.Params {
+		params[i] = p.Name()
+	}
+
+	signature := fmt.Sprintf("(%s) -> %s", strings.Join(params, ", "), f.Return.Name())
+	if len(f.TypeParams) == 0 {
+		return signature
+	}
+
+	type_params := make([]string, len(f.TypeParams))
+	for i, p := range f.TypeParams {
+		type_params[i] = p.Name()
+	}
+
+	return fmt.Sprintf("<%s>%s", strings.Join(type_params, ", "), signature)
+}
+
+func (f *Function) String() string {
+	return typeString(f)
+}
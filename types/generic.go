@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+	"interpreter/ast"
+)
+
+// Type parameter of generic function or class
+// Each declared type parameter is a distinct type
+type TypeVar struct {
+	name string
+}
+
+func NewTypeVar(name string) *TypeVar {
+	return &TypeVar{
+		name: name,
+	}
+}
+
+func (v *TypeVar) Name() string {
+	return v.name
+}
+
+func (v *TypeVar) String() string {
+	return typeString(v)
+}
+
+// Replace type parameters of generic function with unknowns inferred at the call
+// Returns the function without type parameters
+func (c *Checker) instantiate(f *Function, call *ast.CallExpr) *Function {
+	if len(f.TypeParams) == 0 {
+		return f
+	}
+
+	subst := map[*TypeVar]Type{}
+	for _, p := range f.TypeParams {
+		subst[p] = c.fresh(call, fmt.Sprintf("type argument %s", p.name))
+	}
+
+	instance := Substitute(f, subst).(*Function)
+	instance.TypeParams = nil
+	return instance
+}
+
+// Replace type variables in t with their types in subst
+// Variables missing from subst, or mapped to nil, are left unchanged
+func Substitute(t Type, subst map[*TypeVar]Type) Type {
+	switch x := t.(type) {
+	case *TypeVar:
+		if s := subst[x]; s != nil {
+			return s
+		}
+		return x
+	case *Function:
+		params := make([]Type, len(x.Params))
+		for i, p := range x.Params {
+			params[i] = Substitute(p, subst)
+		}
+
+		f := NewFunction(params, Substitute(x.Return, subst))
+		f.TypeParams = x.TypeParams
+		return f
+	case *List:
+		return NewList(Substitute(x.Elem, subst))
+	case *Map:
+		return NewMap(Substitute(x.Key, subst), Substitute(x.Value, subst))
+	case *Nullable:
+		return NewNullable(Substitute(x.Elem, subst))
+	case *Result:
+		return NewResult(Substitute(x.Value, subst), Substitute(x.Error, subst))
+	case *Class:
+		if len(x.Args) == 0 {
+			return x
+		}
+
+		args := make([]Type, len(x.Args))
+		for i, arg := range x.Args {
+			args[i] = Substitute(arg, subst)
+		}
+		return x.origin.Instantiate(args)
+	default:
+		return t
+	}
+}
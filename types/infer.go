@@ -0,0 +1,283 @@
+package types
+
+import (
+	"fmt"
+	"interpreter/ast"
+	"slices"
+)
+
+// Unknown type solved by unification during inference
+type Meta struct {
+	id      int
+	binding Type // Solved type, nil while unknown
+}
+
+func (m *Meta) Name() string {
+	if m.binding != nil {
+		return m.binding.Name()
+	}
+
+	return "?"
+}
+
+func (m *Meta) String() string {
+	return typeString(m)
+}
+
+// Unknown type together with the node it was introduced for
+type inference struct {
+	meta  *Meta
+	node  ast.Node
+	what  string // Description used when the type cannot be inferred
+	quiet bool   // Whether failure to infer is not reported, as it follows from another error
+}
+
+// Follow solved unknowns to the type they stand for
+func prune(t Type) Type {
+	for {
+		m, ok := t.(*Meta)
+		if !ok || m.binding == nil {
+			return t
+		}
+		t = m.binding
+	}
+}
+
+// Replace every solved unknown in t by its solution
+func resolve(t Type) Type {
+	switch x := prune(t).(type) {
+	case *Function:
+		params := make([]Type, len(x.Params))
+		for i, p := range x.Params {
+			params[i] = resolve(p)
+		}
+
+		f := NewFunction(params, resolve(x.Return))
+		f.TypeParams = x.TypeParams
+		return f
+	case *List:
+		return NewList(resolve(x.Elem))
+	case *Map:
+		return NewMap(resolve(x.Key), resolve(x.Value))
+	case *Nullable:
+		return NewNullable(resolve(x.Elem))
+	case *Result:
+		return NewResult(resolve(x.Value), resolve(x.Error))
+	case *Class:
+		if len(x.Args) == 0 {
+			return x
+		}
+
+		args := make([]Type, len(x.Args))
+		for i, arg := range x.Args {
+			args[i] = resolve(arg)
+		}
+		return x.origin.Instantiate(args)
+	default:
+		return x
+	}
+}
+
+// Check if unknown m occurs in t, which would make a solution infinite
+func occurs(m *Meta, t Type) bool {
+	switch x := prune(t).(type) {
+	case *Meta:
+		return x == m
+	case *Function:
+		return slices.ContainsFunc(x.Params, func(p Type) bool { return occurs(m, p) }) || occurs(m, x.Return)
+	case *List:
+		return occurs(m, x.Elem)
+	case *Map:
+		return occurs(m, x.Key) || occurs(m, x.Value)
+	case *Nullable:
+		return occurs(m, x.Elem)
+	case *Result:
+		return occurs(m, x.Value) || occurs(m, x.Error)
+	case *Class:
+		return slices.ContainsFunc(x.Args, func(a Type) bool { return occurs(m, a) })
+	default:
+		return false
+	}
+}
+
+// Check if t contains no unknowns that are not yet solved
+func solved(t Type) bool {
+	switch x := prune(t).(type) {
+	case *Meta:
+		return false
+	case *Function:
+		return !slices.ContainsFunc(x.Params, func(p Type) bool { return !solved(p) }) && solved(x.Return)
+	case *List:
+		return solved(x.Elem)
+	case *Map:
+		return solved(x.Key) && solved(x.Value)
+	case *Nullable:
+		return solved(x.Elem)
+	case *Result:
+		return solved(x.Value) && solved(x.Error)
+	case *Class:
+		return !slices.ContainsFunc(x.Args, func(a Type) bool { return !solved(a) })
+	default:
+		return true
+	}
+}
+
+// Create unknown type for node, reported with description if it is never solved
+func (c *Checker) fresh(node ast.Node, what string) *Meta {
+	m := &Meta{id: len(c.inferences)}
+	c.inferences = append(c.inferences, &inference{
+		meta: m,
+		node: node,
+		what: what,
+	})
+
+	return m
+}
+
+// Make two types equal by solving unknowns in them
+// Returns false if the types cannot be made equal
+func (c *Checker) unify(a Type, b Type) bool {
+	a, b = prune(a), prune(b)
+	if a == nil || b == nil {
+		return false
+	}
+
+	if m, ok := a.(*Meta); ok {
+		return c.bind(m, b)
+	}
+
+	if m, ok := b.(*Meta); ok {
+		return c.bind(m, a)
+	}
+
+	switch x := a.(type) {
+	case *Function:
+		y, ok := b.(*Function)
+		if !ok || len(x.Params) != len(y.Params) {
+			return false
+		}
+
+		for i := range x.Params {
+			if !c.unify(x.Params[i], y.Params[i]) {
+				return false
+			}
+		}
+
+		return c.unify(x.Return, y.Return)
+	case *List:
+		y, ok := b.(*List)
+		return ok && c.unify(x.Elem, y.Elem)
+	case *Map:
+		y, ok := b.(*Map)
+		return ok && c.unify(x.Key, y.Key) && c.unify(x.Value, y.Value)
+	case *Nullable:
+		y, ok := b.(*Nullable)
+		return ok && c.unify(x.Elem, y.Elem)
+	case *Result:
+		y, ok := b.(*Result)
+		return ok && c.unify(x.Value, y.Value) && c.unify(x.Error, y.Error)
+	case *Class:
+		y, ok := b.(*Class)
+		if !ok || x.origin != y.origin || len(x.Args) != len(y.Args) {
+			return false
+		}
+
+		for i := range x.Args {
+			if !c.unify(x.Args[i], y.Args[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return Identical(a, b)
+	}
+}
+
+// Solve unknown m as t
+func (c *Checker) bind(m *Meta, t Type) bool {
+	if t == Type(m) {
+		return true
+	}
+
+	if occurs(m, t) {
+		return false
+	}
+
+	m.binding = t
+	return true
+}
+
+// Widen solution of m to also hold values of type t, e.g. int to int? for null
+// Returns false if m is not solved directly or no type holds both
+func (c *Checker) widen(m *Meta, t Type) bool {
+	if _, chained := m.binding.(*Meta); chained || m.binding == nil || !solved(m.binding) || !solved(t) {
+		return false
+	}
+
+	joined := join(m.binding, prune(t))
+	if joined == nil {
+		return false
+	}
+
+	m.binding = joined
+	return true
+}
+
+// Check if a value of type from can be used where type to is expected,
+// solving unknowns in both types
+func (c *Checker) assignable(from Type, to Type) bool {
+	from, to = prune(from), prune(to)
+	if from == nil || to == nil {
+		return false
+	}
+
+	_, from_meta := from.(*Meta)
+	_, to_meta := to.(*Meta)
+	if from_meta || to_meta {
+		return c.unify(from, to)
+	}
+
+	switch t := to.(type) {
+	case *Nullable:
+		if _, ok := from.(*Null); ok {
+			return true
+		}
+
+		return c.assignable(NonNull(from), t.Elem)
+	case *Interface:
+		if cls, ok := from.(*Class); ok && slices.Contains(cls.Interfaces(), t) {
+			return true
+		}
+	}
+
+	return c.unify(from, to)
+}
+
+// Report unknowns that were never solved and resolve all recorded types
+func (c *Checker) solve() {
+	reported := map[*Meta]bool{}
+	for _, inf := range c.inferences {
+		m, ok := prune(inf.meta).(*Meta)
+		if !ok || reported[m] || inf.quiet {
+			continue
+		}
+		reported[m] = true
+
+		c.error(fmt.Sprintf("Cannot infer %s", inf.what), inf.node)
+	}
+	c.inferences = nil
+
+	for expr, t := range c.Types {
+		c.Types[expr] = resolve(t)
+
+		// Keys of maps whose type was inferred from later use
+		if m, ok := c.Types[expr].(*Map); ok {
+			if _, ok := expr.(*ast.MapLiteral); ok && !isHashable(m.Key) {
+				if _, unknown := m.Key.(*Meta); !unknown {
+					c.error(fmt.Sprintf("Cannot use %s as map key", m.Key.Name()), expr)
+				}
+			}
+		}
+	}
+}
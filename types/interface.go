@@ -0,0 +1,22 @@
+package types
+
+// Type of values of any class implementing the interface
+type Interface struct {
+	name    string
+	Methods map[string]*Function // Signatures of required methods by name
+}
+
+func NewInterface(name string) *Interface {
+	return &Interface{
+		name:    name,
+		Methods: map[string]*Function{},
+	}
+}
+
+func (i *Interface) Name() string {
+	return i.name
+}
+
+func (i *Interface) String() string {
+	return typeString(i)
+}
@@ -0,0 +1,22 @@
+package types
+
+import "fmt"
+
+// Type of lists with elements of a single type
+type List struct {
+	Elem Type // Type of elements
+}
+
+func NewList(elem Type) *List {
+	return &List{
+		Elem: elem,
+	}
+}
+
+func (l *List) Name() string {
+	return fmt.Sprintf("[%s]", l.Elem.Name())
+}
+
+func (l *List) String() string {
+	return typeString(l)
+}
@@ -0,0 +1,34 @@
+package types
+
+import "fmt"
+
+// Type of maps from keys to values
+type Map struct {
+	Key   Type // Type of keys
+	Value Type // Type of values
+}
+
+func NewMap(key Type, value Type) *Map {
+	return &Map{
+		Key:   key,
+		Value: value,
+	}
+}
+
+func (m *Map) Name() string {
+	return fmt.Sprintf("[%s: %s]", m.Key.Name(), m.Value.Name())
+}
+
+func (m *Map) String() string {
+	return typeString(m)
+}
+
+// Check if values of type can be used as map keys
+func isHashable(t Type) bool {
+	p, ok := t.(*Primitive)
+	if !ok {
+		return false
+	}
+
+	return p.kind.isInteger() || p.kind == Char || p.kind == String || p.kind == Boolean
+}
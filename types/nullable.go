@@ -0,0 +1,65 @@
+package types
+
+import "fmt"
+
+// Singleton type
+var null *Null = nil
+
+// Type of the 'null' literal, assignable to every nullable type
+type Null struct{}
+
+func (n *Null) Name() string {
+	return "null"
+}
+
+func (n *Null) String() string {
+	return typeString(n)
+}
+
+// Get singleton
+func NewNull() *Null {
+	if null == nil {
+		null = &Null{}
+	}
+
+	return null
+}
+
+// Type whose values are either of Elem or null
+type Nullable struct {
+	Elem Type // Type of non-null values
+}
+
+// Create nullable type
+// Nullable and null types are returned unchanged
+func NewNullable(elem Type) Type {
+	switch elem.(type) {
+	case *Nullable, *Null:
+		return elem
+	}
+
+	return &Nullable{
+		Elem: elem,
+	}
+}
+
+func (n *Nullable) Name() string {
+	if _, ok := n.Elem.(*Function); ok {
+		return fmt.Sprintf("(%s)?", n.Elem.Name())
+	}
+
+	return fmt.Sprintf("%s?", n.Elem.Name())
+}
+
+func (n *Nullable) String() string {
+	return typeString(n)
+}
+
+// Get type with null removed
+func NonNull(t Type) Type {
+	if n, ok := t.(*Nullable); ok {
+		return n.Elem
+	}
+
+	return t
+}
@@ -0,0 +1,177 @@
+package types
+
+import (
+	"interpreter/token"
+	"math"
+	"strconv"
+)
+
+// Name of kind as written in programs
+func (k PrimitiveKind) String() string {
+	switch k {
+	case Char:
+		return "char"
+	case String:
+		return "string"
+	case Boolean:
+		return "boolean"
+	case Unit:
+		return "unit"
+	case Int:
+		return "int"
+	case Real:
+		return "real"
+	case I8:
+		return "i8"
+	case I16:
+		return "i16"
+	case I32:
+		return "i32"
+	case U8:
+		return "u8"
+	case U16:
+		return "u16"
+	case U32:
+		return "u32"
+	case U64:
+		return "u64"
+	case F32:
+		return "f32"
+	case BigInt:
+		return "bigint"
+	case Decimal:
+		return "decimal"
+	default:
+		return "undefined"
+	}
+}
+
+// Check if kind is a signed or unsigned integer, including bigint
+func (k PrimitiveKind) isInteger() bool {
+	switch k {
+	case Int, I8, I16, I32, U8, U16, U32, U64, BigInt:
+		return true
+	default:
+		return false
+	}
+}
+
+// Check if kind is a floating point number
+func (k PrimitiveKind) isFloat() bool {
+	return k == Real || k == F32
+}
+
+// Check if kind is any number
+func (k PrimitiveKind) isNumeric() bool {
+	return k.isInteger() || k.isFloat() || k == Decimal
+}
+
+// Check if kind is an unsigned integer
+func (k PrimitiveKind) isUnsigned() bool {
+	switch k {
+	case U8, U16, U32, U64:
+		return true
+	default:
+		return false
+	}
+}
+
+// Number of bits used by numeric kind
+func (k PrimitiveKind) bits() int {
+	switch k {
+	case I8, U8:
+		return 8
+	case I16, U16:
+		return 16
+	case I32, U32, F32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// Get numeric type converted to by method with name, e.g. 'toU8'
+func conversion(name string) *Primitive {
+	// Aliases of toI64 and toF64
+	switch name {
+	case "toInt":
+		return NewInteger()
+	case "toReal":
+		return NewReal()
+	}
+
+	for _, kind := range []PrimitiveKind{Int, Real, I8, I16, I32, U8, U16, U32, U64, F32, BigInt, Decimal} {
+		if name == "to"+conversionName(kind) {
+			return NewNumeric(kind)
+		}
+	}
+
+	return nil
+}
+
+// Name of numeric kind in conversion methods, e.g. 'U8' in 'toU8'
+func conversionName(k PrimitiveKind) string {
+	switch k {
+	case Int:
+		return "I64"
+	case Real:
+		return "F64"
+	case BigInt:
+		return "BigInt"
+	case Decimal:
+		return "Decimal"
+	default:
+		return string(k.String()[0]-'a'+'A') + k.String()[1:]
+	}
+}
+
+// Check if literal fits in numeric kind
+// Value may start with '-'
+func fitsLiteral(value string, k PrimitiveKind) bool {
+	switch {
+	case k == BigInt || k == Decimal:
+		return true
+	case k.isUnsigned():
+		_, err := strconv.ParseUint(value, 10, k.bits())
+		return err == nil || value == "-0"
+	case k.isInteger():
+		_, err := strconv.ParseInt(value, 10, k.bits())
+		return err == nil
+	default:
+		f, err := strconv.ParseFloat(value, k.bits())
+		return err == nil && !math.IsInf(f, 0)
+	}
+}
+
+// Check if type is a non-null number
+func isNumeric(t Type) bool {
+	p, ok := t.(*Primitive)
+	return ok && p.kind.isNumeric()
+}
+
+// Get type of number literal without expected type
+func literalKind(kind token.TokenType) PrimitiveKind {
+	switch kind {
+	case token.REAL:
+		return Real
+	case token.BIGINT:
+		return BigInt
+	case token.DECIMAL:
+		return Decimal
+	default:
+		return Int
+	}
+}
+
+// Check if number literal of token kind can have numeric kind
+// Integer literals can have any integer type, and real literals any floating point type or decimal
+func literalOf(kind token.TokenType, k PrimitiveKind) bool {
+	switch kind {
+	case token.INTEGER:
+		return k.isInteger()
+	case token.REAL:
+		return k.isFloat() || k == Decimal
+	default:
+		return literalKind(kind) == k
+	}
+}
@@ -9,6 +9,21 @@ const (
 	Char
 	String
 	Boolean
+	Unit
+
+	// Sized numeric types, int and real are the 64 bit ones
+	I8
+	I16
+	I32
+	U8
+	U16
+	U32
+	U64
+	F32
+
+	// Arbitrary precision numeric types
+	BigInt
+	Decimal
 )
 
 // Singleton types
@@ -18,6 +33,8 @@ var double *Primitive = nil
 var char *Primitive = nil
 var text *Primitive = nil
 var boolean *Primitive = nil
+var unit *Primitive = nil
+var numerics = map[PrimitiveKind]*Primitive{}
 
 type Primitive struct {
 	kind PrimitiveKind
@@ -100,6 +117,19 @@ func NewBoolean() *Primitive {
 	return boolean
 }
 
+func NewUnit() *Primitive {
+	if unit != nil {
+		return unit
+	}
+
+	unit = &Primitive{
+		kind: Unit,
+		name: "unit",
+	}
+
+	return unit
+}
+
 func NewUndefined() *Primitive {
 	if undefined != nil {
 		return undefined
@@ -113,6 +143,28 @@ func NewUndefined() *Primitive {
 	return undefined
 }
 
+// Get singleton of numeric type
+func NewNumeric(kind PrimitiveKind) *Primitive {
+	switch kind {
+	case Int:
+		return NewInteger()
+	case Real:
+		return NewReal()
+	}
+
+	if p, ok := numerics[kind]; ok {
+		return p
+	}
+
+	p := &Primitive{
+		kind: kind,
+		name: kind.String(),
+	}
+	numerics[kind] = p
+
+	return p
+}
+
 // Initialize inbuilt types
 func getPrimitives() map[string]Type {
 	types := map[string]Type{}
@@ -122,6 +174,14 @@ func getPrimitives() map[string]Type {
 	types["string"] = NewString()
 	types["boolean"] = NewBoolean()
 	types["char"] = NewChar()
+	types["unit"] = NewUnit()
+
+	// int and real are also named by their size
+	types["i64"] = NewInteger()
+	types["f64"] = NewReal()
+	for _, kind := range []PrimitiveKind{I8, I16, I32, U8, U16, U32, U64, F32, BigInt, Decimal} {
+		types[kind.String()] = NewNumeric(kind)
+	}
 
 	return types
 }
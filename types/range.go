@@ -0,0 +1,24 @@
+package types
+
+// Singleton type
+var intRange *Range = nil
+
+// Type of integer ranges
+type Range struct{}
+
+func (r *Range) Name() string {
+	return "range"
+}
+
+func (r *Range) String() string {
+	return typeString(r)
+}
+
+// Get singleton
+func NewRange() *Range {
+	if intRange == nil {
+		intRange = &Range{}
+	}
+
+	return intRange
+}
@@ -0,0 +1,25 @@
+package types
+
+import "fmt"
+
+// Type of results of operations that can fail
+// Values are either Ok with a value, or Err with an error
+type Result struct {
+	Value Type // Type of value of successful results
+	Error Type // Type of error of failed results
+}
+
+func NewResult(value Type, err Type) *Result {
+	return &Result{
+		Value: value,
+		Error: err,
+	}
+}
+
+func (r *Result) Name() string {
+	return fmt.Sprintf("Result<%s, %s>", r.Value.Name(), r.Error.Name())
+}
+
+func (r *Result) String() string {
+	return typeString(r)
+}
@@ -1,8 +1,6 @@
 package types
 
 import (
-	"errors"
-	"fmt"
 	"interpreter/ast"
 	"interpreter/token"
 )
@@ -16,31 +14,69 @@ type variable struct {
 	name        string
 	kind        Type
 	mutable     bool
-	initialized bool
+	initialized bool      // Whether variable is assigned on every path to the current point
+	assigned    bool      // Whether variable is assigned on some path to the current point
+	origin      *variable // Variable this is a narrowed view of (nil if not narrowed)
+	captured    bool      // Whether a lambda or function assigns the variable, which prevents narrowing
 }
 
 func (v *variable) Symbol()    {}
 func (v *variable) Type() Type { return v.kind }
 
-func newVariable(stmt *ast.VarDeclaration, t Type, symbols map[string]symbol) (*variable, error) {
-	cur, ok := symbols[t.Name()]
-	if ok {
-		if t.Name() != cur.Type().Name() {
-			return nil, errors.New(fmt.Sprintf("Redifinition of %s with different type at line %d.", stmt.Name, stmt.Pos.Row))
-		}
+// Get the declared variable behind narrowed views
+func (v *variable) declared() *variable {
+	for v.origin != nil {
+		v = v.origin
 	}
+
+	return v
+}
+
+func newVariable(stmt *ast.VarDeclaration, t Type) *variable {
 	return &variable{
 		name:        stmt.Name,
 		kind:        t,
 		mutable:     stmt.DeclType == token.VAR,
 		initialized: stmt.Value != nil,
-	}, nil
+		assigned:    stmt.Value != nil,
+	}
 }
 
 type function struct {
 	name string
-	kind Type
+	kind *Function
+	decl *ast.FunDeclaration
 }
 
 func (f *function) Symbol()    {}
 func (f *function) Type() Type { return f.kind }
+
+// Class name used as constructor
+type class struct {
+	name string
+	kind *Class
+	decl *ast.ClassDeclaration
+}
+
+func (c *class) Symbol()    {}
+func (c *class) Type() Type { return c.kind.Constructor() }
+
+// Enum name used to access variants
+type enum struct {
+	name string
+	kind *Enum
+	decl *ast.EnumDeclaration
+}
+
+func (e *enum) Symbol()    {}
+func (e *enum) Type() Type { return e.kind }
+
+// Interface name, which is only usable as a type
+type iface struct {
+	name string
+	kind *Interface
+	decl *ast.InterfaceDeclaration
+}
+
+func (i *iface) Symbol()    {}
+func (i *iface) Type() Type { return i.kind }
@@ -1,5 +1,78 @@
 package types
 
+import "slices"
+
 type Type interface {
 	Name() string
 }
+
+// Check if two types are structurally identical
+func Identical(a Type, b Type) bool {
+	a, b = prune(a), prune(b)
+	if a == nil || b == nil {
+		return false
+	}
+
+	switch x := a.(type) {
+	case *Function:
+		y, ok := b.(*Function)
+		if !ok || len(x.Params) != len(y.Params) {
+			return false
+		}
+
+		for i := range x.Params {
+			if !Identical(x.Params[i], y.Params[i]) {
+				return false
+			}
+		}
+
+		return Identical(x.Return, y.Return)
+	case *List:
+		y, ok := b.(*List)
+		return ok && Identical(x.Elem, y.Elem)
+	case *Map:
+		y, ok := b.(*Map)
+		return ok && Identical(x.Key, y.Key) && Identical(x.Value, y.Value)
+	case *Nullable:
+		y, ok := b.(*Nullable)
+		return ok && Identical(x.Elem, y.Elem)
+	case *Result:
+		y, ok := b.(*Result)
+		return ok && Identical(x.Value, y.Value) && Identical(x.Error, y.Error)
+	case *Class:
+		y, ok := b.(*Class)
+		if !ok || x.origin != y.origin || len(x.Args) != len(y.Args) {
+			return false
+		}
+
+		for i := range x.Args {
+			if !Identical(x.Args[i], y.Args[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return a == b
+	}
+}
+
+// Check if a value of type from can be used where type to is expected
+func Assignable(from Type, to Type) bool {
+	from, to = prune(from), prune(to)
+	if Identical(from, to) {
+		return true
+	}
+
+	switch t := to.(type) {
+	case *Nullable:
+		_, isNull := from.(*Null)
+		return isNull || Assignable(NonNull(from), t.Elem)
+	case *Interface:
+		// Classes are used as the interfaces they declare to implement
+		cls, ok := from.(*Class)
+		return ok && slices.Contains(cls.Interfaces(), t)
+	default:
+		return false
+	}
+}
@@ -6,6 +6,33 @@ func typeString(t Type) string {
 	case *Primitive:
 		p := t.(*Primitive)
 		return p.Name()
+	case *Function:
+		f := t.(*Function)
+		return f.Name()
+	case *List:
+		l := t.(*List)
+		return l.Name()
+	case *Map:
+		m := t.(*Map)
+		return m.Name()
+	case *Class:
+		return t.Name()
+	case *Enum:
+		return t.Name()
+	case *Interface:
+		return t.Name()
+	case *Nullable:
+		return t.Name()
+	case *Null:
+		return t.Name()
+	case *TypeVar:
+		return t.Name()
+	case *Meta:
+		return t.Name()
+	case *Range:
+		return t.Name()
+	case *Result:
+		return t.Name()
 	default:
 		return "illegal"
 	}